@@ -24,6 +24,7 @@ var (
 	flConfigPath = fs.String("config", "config.toml", "Path to config file")
 	flVerbose    = fs.Bool("verbose", false, "enable debug output")
 	flVerboseSql = fs.Bool("verbose-sql", false, "enable all sql output")
+	flShutdown   = fs.Duration("shutdown-timeout", 5*time.Second, "time to finish bot handlers and workers on shutdown")
 	cfg          app.Config
 	version      string
 )
@@ -65,8 +66,7 @@ func main() {
 		}
 	}()
 	<-quit
-	application.Shutdown(5 * time.Second)
-
+	application.Shutdown(*flShutdown)
 }
 
 // fixStdLog sets additional params to std logger (prefix D, filename & line).
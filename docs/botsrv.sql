@@ -129,3 +129,274 @@ ALTER TABLE "vfsFolders" ADD CONSTRAINT "vfsFolders_statusId_fkey" FOREIGN KEY (
 	NOT DEFERRABLE;
 
 
+CREATE TABLE "applications" (
+	"applicationId" SERIAL NOT NULL,
+	"tgId" int8 NOT NULL,
+	"role" varchar(32) NOT NULL,
+	"form" jsonb NOT NULL,
+	"status" varchar(16) NOT NULL DEFAULT 'pending',
+	"adminMessageId" int4,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"updatedAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"decidedAt" timestamp with time zone,
+	"rejectReason" text,
+	"notifiedAt" timestamp with time zone,
+	CONSTRAINT "applications_pkey" PRIMARY KEY("applicationId")
+);
+
+CREATE INDEX "IX_applications_tgId" ON "applications" USING BTREE (
+	"tgId"
+);
+
+
+CREATE INDEX "IX_applications_status" ON "applications" USING BTREE (
+	"status"
+);
+
+
+CREATE TABLE "moderationLogs" (
+	"moderationLogId" SERIAL NOT NULL,
+	"applicationId" int4,
+	"targetTgId" int8 NOT NULL,
+	"actorTgId" int8 NOT NULL,
+	"actorName" varchar(255) NOT NULL,
+	"action" varchar(16) NOT NULL,
+	"statusBefore" varchar(16),
+	"statusAfter" varchar(16),
+	"comment" text,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT "moderationLogs_pkey" PRIMARY KEY("moderationLogId")
+);
+
+CREATE INDEX "IX_FK_moderationLogs_applicationId_moderationLogs" ON "moderationLogs" USING BTREE (
+	"applicationId"
+);
+
+
+CREATE INDEX "IX_moderationLogs_createdAt" ON "moderationLogs" USING BTREE (
+	"createdAt"
+);
+
+
+-- moderation log is append-only
+CREATE RULE "moderationLogs_no_update" AS ON UPDATE TO "moderationLogs" DO INSTEAD NOTHING;
+CREATE RULE "moderationLogs_no_delete" AS ON DELETE TO "moderationLogs" DO INSTEAD NOTHING;
+
+ALTER TABLE "moderationLogs" ADD CONSTRAINT "moderationLogs_applicationId_fkey" FOREIGN KEY ("applicationId")
+	REFERENCES "applications"("applicationId")
+	MATCH SIMPLE
+	ON DELETE RESTRICT
+	ON UPDATE RESTRICT
+	NOT DEFERRABLE;
+
+
+CREATE TABLE "applicationVotes" (
+	"applicationVoteId" SERIAL NOT NULL,
+	"applicationId" int4 NOT NULL,
+	"voterTgId" int8 NOT NULL,
+	"voterName" varchar(255) NOT NULL,
+	"vote" varchar(16) NOT NULL,
+	"comment" text,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT "applicationVotes_pkey" PRIMARY KEY("applicationVoteId")
+);
+
+CREATE UNIQUE INDEX "IX_applicationVotes_applicationId_voterTgId" ON "applicationVotes" USING BTREE (
+	"applicationId",
+	"voterTgId"
+);
+
+
+ALTER TABLE "applicationVotes" ADD CONSTRAINT "applicationVotes_applicationId_fkey" FOREIGN KEY ("applicationId")
+	REFERENCES "applications"("applicationId")
+	MATCH SIMPLE
+	ON DELETE RESTRICT
+	ON UPDATE RESTRICT
+	NOT DEFERRABLE;
+
+
+CREATE TABLE "inviteLinks" (
+	"inviteLinkId" SERIAL NOT NULL,
+	"applicationId" int4 NOT NULL,
+	"tgId" int8 NOT NULL,
+	"link" varchar(255) NOT NULL,
+	"expiresAt" timestamp with time zone NOT NULL,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"usedAt" timestamp with time zone,
+	"revokedAt" timestamp with time zone,
+	CONSTRAINT "inviteLinks_pkey" PRIMARY KEY("inviteLinkId"),
+	CONSTRAINT "inviteLinks_link_key" UNIQUE("link")
+);
+
+CREATE INDEX "IX_FK_inviteLinks_applicationId_inviteLinks" ON "inviteLinks" USING BTREE (
+	"applicationId"
+);
+
+
+CREATE INDEX "IX_inviteLinks_expiresAt" ON "inviteLinks" USING BTREE (
+	"expiresAt"
+);
+
+
+ALTER TABLE "inviteLinks" ADD CONSTRAINT "inviteLinks_applicationId_fkey" FOREIGN KEY ("applicationId")
+	REFERENCES "applications"("applicationId")
+	MATCH SIMPLE
+	ON DELETE RESTRICT
+	ON UPDATE RESTRICT
+	NOT DEFERRABLE;
+
+
+CREATE TABLE "conversations" (
+	"conversationId" SERIAL NOT NULL,
+	"tgId" int8 NOT NULL,
+	"role" varchar(32) NOT NULL,
+	"step" int4 NOT NULL DEFAULT 0,
+	"form" jsonb NOT NULL DEFAULT '{}',
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"updatedAt" timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT "conversations_pkey" PRIMARY KEY("conversationId"),
+	CONSTRAINT "conversations_tgId_key" UNIQUE("tgId")
+);
+
+
+CREATE TABLE "registrationTokens" (
+	"registrationTokenId" SERIAL NOT NULL,
+	"token" varchar(64) NOT NULL,
+	"tgId" int8 NOT NULL,
+	"username" varchar(255) NOT NULL,
+	"role" varchar(32) NOT NULL,
+	"expiresAt" timestamp with time zone NOT NULL,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"usedAt" timestamp with time zone,
+	CONSTRAINT "registrationTokens_pkey" PRIMARY KEY("registrationTokenId"),
+	CONSTRAINT "registrationTokens_token_key" UNIQUE("token")
+);
+
+
+CREATE TABLE "outboxMessages" (
+	"outboxMessageId" SERIAL NOT NULL,
+	"method" varchar(32) NOT NULL,
+	"chatId" int8 NOT NULL,
+	"payload" jsonb NOT NULL,
+	"applicationId" int4,
+	"kind" varchar(32),
+	"status" varchar(16) NOT NULL,
+	"attempts" int4 NOT NULL DEFAULT 0,
+	"nextAttemptAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"lastError" text,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"sentAt" timestamp with time zone,
+	CONSTRAINT "outboxMessages_pkey" PRIMARY KEY("outboxMessageId")
+);
+
+CREATE INDEX "IX_outboxMessages_status_nextAttemptAt" ON "outboxMessages" USING BTREE (
+	"status",
+	"nextAttemptAt"
+);
+
+CREATE INDEX "IX_outboxMessages_chatId_status" ON "outboxMessages" USING BTREE (
+	"chatId",
+	"status"
+);
+
+CREATE INDEX "IX_FK_outboxMessages_applicationId_outboxMessages" ON "outboxMessages" USING BTREE (
+	"applicationId"
+);
+
+ALTER TABLE "outboxMessages" ADD CONSTRAINT "outboxMessages_applicationId_fkey" FOREIGN KEY ("applicationId")
+	REFERENCES "applications"("applicationId")
+	MATCH SIMPLE
+	ON DELETE RESTRICT
+	ON UPDATE RESTRICT
+	NOT DEFERRABLE;
+
+
+
+
+CREATE TABLE "processedUpdates" (
+	"updateId" int8 NOT NULL,
+	"payload" jsonb NOT NULL,
+	"status" varchar(16) NOT NULL,
+	"claimedAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"owner" varchar(64) NOT NULL,
+	"heartbeatAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"processedAt" timestamp with time zone,
+	CONSTRAINT "processedUpdates_pkey" PRIMARY KEY("updateId")
+);
+
+CREATE INDEX "IX_processedUpdates_status_heartbeatAt" ON "processedUpdates" USING BTREE (
+	"status",
+	"heartbeatAt"
+);
+
+
+CREATE TABLE "members" (
+	"memberId" SERIAL NOT NULL,
+	"tgId" int8 NOT NULL,
+	"username" varchar(64),
+	"role" varchar(32) NOT NULL,
+	"name" varchar(255) NOT NULL,
+	"year" int4,
+	"class" varchar(8),
+	"cities" varchar[] NOT NULL DEFAULT '{}',
+	"universities" varchar[] NOT NULL DEFAULT '{}',
+	"work" text,
+	"extra" text,
+	"applicationId" int4,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	"updatedAt" timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT "members_pkey" PRIMARY KEY("memberId"),
+	CONSTRAINT "members_tgId_key" UNIQUE("tgId")
+);
+
+CREATE INDEX "IX_FK_members_applicationId_applications" ON "members" USING BTREE (
+	"applicationId"
+);
+
+CREATE INDEX "IX_members_cities" ON "members" USING GIN ("cities");
+
+CREATE INDEX "IX_members_universities" ON "members" USING GIN ("universities");
+
+ALTER TABLE "members" ADD CONSTRAINT "members_applicationId_fkey" FOREIGN KEY ("applicationId")
+	REFERENCES "applications"("applicationId")
+	MATCH SIMPLE
+	ON DELETE RESTRICT
+	ON UPDATE RESTRICT
+	NOT DEFERRABLE;
+
+
+CREATE TABLE "terms" (
+	"termId" SERIAL NOT NULL,
+	"kind" varchar(16) NOT NULL,
+	"name" varchar(255) NOT NULL,
+	"status" varchar(16) NOT NULL,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT "terms_pkey" PRIMARY KEY("termId")
+);
+
+CREATE INDEX "IX_terms_kind_status" ON "terms" USING BTREE (
+	"kind",
+	"status"
+);
+
+
+CREATE TABLE "termAliases" (
+	"termAliasId" SERIAL NOT NULL,
+	"termId" int4 NOT NULL,
+	"kind" varchar(16) NOT NULL,
+	"alias" varchar(255) NOT NULL,
+	"createdAt" timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT "termAliases_pkey" PRIMARY KEY("termAliasId"),
+	CONSTRAINT "termAliases_kind_alias_key" UNIQUE("kind", "alias")
+);
+
+CREATE INDEX "IX_FK_termAliases_termId_terms" ON "termAliases" USING BTREE (
+	"termId"
+);
+
+ALTER TABLE "termAliases" ADD CONSTRAINT "termAliases_termId_fkey" FOREIGN KEY ("termId")
+	REFERENCES "terms"("termId")
+	MATCH SIMPLE
+	ON DELETE CASCADE
+	ON UPDATE RESTRICT
+	NOT DEFERRABLE;
@@ -3,6 +3,7 @@ package app
 import (
 	"botsrv/pkg/botsrv"
 	"context"
+	"errors"
 	"github.com/go-telegram/bot/models"
 	"io"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"github.com/go-pg/pg/v10"
 	"github.com/go-telegram/bot"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/vmkteam/zenrpc/v2"
 )
 
@@ -25,9 +27,30 @@ type Config struct {
 		IsDevel   bool
 		EnableVFS bool
 	}
-	Bot botsrv.Config
+	Bot   botsrv.Config
+	Forms struct {
+		// Secret is a shared secret of HMAC signature of form webhooks, all webhooks are rejected if empty.
+		Secret string
+		// MaxAge is a replay window of signed webhooks, timestamp is not checked if zero.
+		MaxAge time.Duration
+		// Tokens are secret tokens of form webhooks by role passed in the secret query parameter of the webhook URL,
+		// they authenticate native webhooks of form tools which could not sign requests, e.g. Yandex Forms.
+		Tokens map[string]string
+	}
+	Webhook struct {
+		// URL is a public URL of RouteTelegramWebhook behind the reverse proxy, updates are received by long polling if empty.
+		URL string
+		// SecretToken is sent by Telegram in X-Telegram-Bot-Api-Secret-Token header, derived from the bot token if empty.
+		SecretToken string
+		// MaxConnections is a number of simultaneous webhook connections, 40 by default.
+		MaxConnections int
+	}
 }
 
+// allowedUpdates are types of updates handled by the bot, chat_member updates are not sent by default
+// and the bot must be an administrator of the lyceum chat to receive them.
+var allowedUpdates = bot.AllowedUpdates{models.AllowedUpdateMessage, models.AllowedUpdateCallbackQuery, models.AllowedUpdateChatMember, models.AllowedUpdateChatJoinRequest}
+
 type App struct {
 	embedlog.Logger
 	appName string
@@ -39,6 +62,20 @@ type App struct {
 
 	b  *bot.Bot
 	bm *botsrv.BotManager
+
+	// ctx is a root context cancelled on shutdown
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers *workers
+	// heartbeat is a context of refreshing claims of updates, it is cancelled after bot handlers are drained
+	heartbeat     context.Context
+	stopHeartbeat context.CancelFunc
+
+	statFormRejected *prometheus.CounterVec
+	statLeader       prometheus.Gauge
+
+	// leader is 1 while the replica holds the leader lock
+	leader int32
 }
 
 func New(appName string, verbose bool, cfg Config, db db.DB, dbc *pg.DB) *App {
@@ -48,15 +85,28 @@ func New(appName string, verbose bool, cfg Config, db db.DB, dbc *pg.DB) *App {
 		db:      db,
 		dbc:     dbc,
 		echo:    echo.New(),
+		workers: newWorkers(),
 	}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+	a.heartbeat, a.stopHeartbeat = context.WithCancel(context.Background())
 	a.SetStdLoggers(verbose)
 	a.echo.HideBanner = true
 	a.echo.HidePort = true
 	a.echo.IPExtractor = echo.ExtractIPFromRealIPHeader()
 
-	a.bm = botsrv.NewBotManager(a.Logger, a.db, a.cfg.Bot)
-
-	opts := []bot.Option{bot.WithDefaultHandler(a.bm.PrivateOnly(a.bm.DefaultHandler))}
+	bm, err := botsrv.NewBotManager(a.Logger, a.db, a.cfg.Bot)
+	if err != nil {
+		panic(err)
+	}
+	a.bm = bm
+
+	opts := []bot.Option{
+		bot.WithDefaultHandler(a.bm.PrivateOnly(a.bm.ProcessOnce(a.bm.DefaultHandler))),
+		bot.WithAllowedUpdates(allowedUpdates),
+		bot.WithWebhookSecretToken(a.webhookSecret()),
+		bot.WithMiddlewares(a.trackHandler),
+		bot.WithInitialOffset(a.bm.LastUpdateID(context.Background())),
+	}
 	b, err := bot.New(cfg.Bot.Token, opts...)
 	if err != nil {
 		panic(err)
@@ -74,43 +124,47 @@ func (a *App) Run() error {
 	a.registerAPIHandlers()
 
 	a.bm.RegisterBotHandlers(a.b)
-	go a.b.Start(context.TODO())
+	go a.bm.RunHeartbeat(a.heartbeat)
+	a.startUpdates()
+	a.goWorker(a.ctx, "election", a.runElection)
 	return a.runHTTPServer(a.cfg.Server.Host, a.cfg.Server.Port)
 }
 
-// Shutdown is a function that gracefully stops HTTP server.
-func (a *App) Shutdown(timeout time.Duration) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	if err := a.echo.Shutdown(ctx); err != nil {
-		a.Errorf("shutting down server err=%q", err)
-	}
-}
-
 func (a *App) handleFormResult(c echo.Context) error {
 	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
 		a.Errorf("%v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Ошибка чтения тела запроса",
-		})
-	}
-
-	update := &models.Update{
-		CallbackQuery: &models.CallbackQuery{
-			Data: string(body),
-		},
+		return readBodyError(c, err)
 	}
 
-	switch c.Path() {
-	case RouteSubmitStudentForm:
-		a.bm.ModerationStudent(c.Request().Context(), a.b, update)
-	case RouteSubmitGraduateForm:
-		a.bm.ModerationGraduate(c.Request().Context(), a.b, update)
-	default:
+	role := formRole(c)
+	var verr botsrv.ValidationError
+	err = a.bm.SubmitFormResult(c.Request().Context(), a.b, role, c.Request().Header.Get(echo.HeaderContentType), body)
+	if errors.As(err, &verr) {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":  "Анкета заполнена некорректно",
+			"fields": verr,
+		})
+	} else if errors.Is(err, botsrv.ErrUnknownRole) {
 		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Неизвестный путь",
+			"error": "Неизвестная роль",
+		})
+	} else if errors.Is(err, botsrv.ErrInvalidToken) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Недействительный токен регистрации",
+		})
+	} else if errors.Is(err, botsrv.ErrBanned) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Пользователь заблокирован",
+		})
+	} else if errors.Is(err, botsrv.ErrInvalidPayload) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Ошибка обработки анкеты",
+		})
+	} else if err != nil {
+		// the form tool retries the webhook, the registration token is still valid
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Не удалось сохранить анкету, повторите запрос позже",
 		})
 	}
 
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"botsrv/pkg/botsrv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderFormSignature = "X-Signature"
+	HeaderFormTimestamp = "X-Timestamp"
+	// HeaderTallySignature is a signature of native Tally webhooks: base64 HMAC-SHA256 of the body with the signing secret.
+	HeaderTallySignature = "Tally-Signature"
+	// QueryFormToken is a query parameter of the webhook URL with the secret token of the role.
+	QueryFormToken = "secret"
+	// maxFormBodySize is a maximum size of the form webhook body.
+	maxFormBodySize = 1 << 20
+)
+
+// formRole returns role of the form webhook by its route.
+func formRole(c echo.Context) string {
+	switch c.Path() {
+	case RouteSubmitStudentForm:
+		return botsrv.RoleStudent
+	case RouteSubmitGraduateForm:
+		return botsrv.RoleGraduate
+	}
+
+	return c.Param("role")
+}
+
+// readBodyError returns response to the form webhook whose body could not be read.
+func readBodyError(c echo.Context, err error) error {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "Слишком большой запрос",
+		})
+	}
+
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Ошибка чтения тела запроса",
+	})
+}
+
+// formSignature returns hex HMAC-SHA256 of "<timestamp>.<body>" with the shared secret.
+func formSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// tallySignature returns base64 HMAC-SHA256 of the body with the shared secret.
+func tallySignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// formAuth is the middleware function that verifies signature of the form webhook before the payload is processed.
+// Timestamp is checked against replay window if it is configured, native Tally signature is accepted as well.
+// Webhooks of roles with configured token are accepted by the token in the URL instead of the signature.
+// The body is limited by maxFormBodySize for the handler as well.
+func (a *App) formAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	reject := func(c echo.Context, reason string) error {
+		a.statFormRejected.WithLabelValues(reason).Inc()
+		a.Printf("form webhook rejected reason=%s ip=%s", reason, c.RealIP())
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Запрос не подписан",
+		})
+	}
+
+	return func(c echo.Context) error {
+		c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxFormBodySize)
+
+		if token := a.cfg.Forms.Tokens[formRole(c)]; token != "" {
+			if subtle.ConstantTimeCompare([]byte(c.QueryParam(QueryFormToken)), []byte(token)) != 1 {
+				return reject(c, "token")
+			}
+			return next(c)
+		}
+
+		if a.cfg.Forms.Secret == "" {
+			return reject(c, "disabled")
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return readBodyError(c, err)
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		// Tally webhooks are signed without timestamp
+		if signature := c.Request().Header.Get(HeaderTallySignature); signature != "" {
+			if !hmac.Equal([]byte(signature), []byte(tallySignature(a.cfg.Forms.Secret, body))) {
+				return reject(c, "signature")
+			}
+			return next(c)
+		}
+
+		timestamp := c.Request().Header.Get(HeaderFormTimestamp)
+		if a.cfg.Forms.MaxAge > 0 {
+			ts, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return reject(c, "timestamp")
+			}
+
+			if age := time.Since(time.Unix(ts, 0)); age > a.cfg.Forms.MaxAge || age < -a.cfg.Forms.MaxAge {
+				return reject(c, "expired")
+			}
+		}
+
+		signature := c.Request().Header.Get(HeaderFormSignature)
+		if !hmac.Equal([]byte(signature), []byte(formSignature(a.cfg.Forms.Secret, timestamp, body))) {
+			return reject(c, "signature")
+		}
+
+		return next(c)
+	}
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
@@ -16,8 +17,13 @@ import (
 )
 
 const (
+	RouteSubmitForm = "/form/:role"
+	// legacy form webhooks of the default roles
 	RouteSubmitStudentForm  = "/formstudent"
 	RouteSubmitGraduateForm = "/formgraduate"
+	RouteWebApp             = "/webapp/"
+	RouteWebAppSubmit       = "/webapp/submit"
+	RouteTelegramWebhook    = "/telegram/webhook"
 )
 
 // runHTTPServer is a function that starts http listener using labstack/echo.
@@ -25,7 +31,11 @@ func (a *App) runHTTPServer(host string, port int) error {
 	listenAddress := fmt.Sprintf("%s:%d", host, port)
 	a.Printf("starting http listener at http://%s\n", listenAddress)
 
-	return a.echo.Start(listenAddress)
+	if err := a.echo.Start(listenAddress); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
 }
 
 func (a *App) registerHandlers() {
@@ -71,8 +81,14 @@ func (a *App) registerAPIHandlers() {
 	srv := rpc.New(a.db, a.Logger, a.cfg.Server.IsDevel)
 	gen := rpcgen.FromSMD(srv.SMD())
 
-	a.echo.Any(RouteSubmitStudentForm, a.handleFormResult)
-	a.echo.Any(RouteSubmitGraduateForm, a.handleFormResult)
+	a.echo.POST(RouteSubmitForm, a.handleFormResult, a.formAuth)
+	a.echo.POST(RouteSubmitStudentForm, a.handleFormResult, a.formAuth)
+	a.echo.POST(RouteSubmitGraduateForm, a.handleFormResult, a.formAuth)
+	a.echo.GET(RouteWebApp, a.handleWebApp)
+	a.echo.POST(RouteWebAppSubmit, a.handleWebAppSubmit)
+	if a.isWebhook() {
+		a.echo.POST(RouteTelegramWebhook, echo.WrapHandler(a.b.WebhookHandler()), a.webhookAuth, a.rejectOnShutdown)
+	}
 
 	a.echo.Any("/v1/rpc/", zm.EchoHandler(zm.XRequestID(srv)))
 	a.echo.Any("/v1/rpc/doc/", echo.WrapHandler(http.HandlerFunc(zenrpc.SMDBoxHandler)))
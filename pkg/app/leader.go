@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"botsrv/pkg/botsrv"
+	"botsrv/pkg/db"
+)
+
+const (
+	// leaderLock is a name of the advisory lock held by the replica which polls Telegram and runs background jobs.
+	leaderLock = "botsrv:leader"
+	// electionInterval is a period of taking the leadership and checking that it is still held.
+	electionInterval = 3 * time.Second
+)
+
+// leadership is a term of the replica as the leader.
+type leadership struct {
+	lock   *db.SessionLock
+	cancel context.CancelFunc
+	jobs   sync.WaitGroup
+}
+
+// isLeader checks if the replica is the leader.
+func (a *App) isLeader() bool {
+	return atomic.LoadInt32(&a.leader) == 1
+}
+
+// setLeader stores the leadership state and exports it as the metric.
+func (a *App) setLeader(leader bool) {
+	var v int32
+	if leader {
+		v = 1
+	}
+
+	atomic.StoreInt32(&a.leader, v)
+	a.statLeader.Set(float64(v))
+}
+
+// runElection takes the leadership when no other replica holds the lock and keeps it until the connection is lost or ctx is done.
+// Only the leader polls Telegram and runs background jobs, all replicas serve HTTP.
+func (a *App) runElection(ctx context.Context) {
+	ticker := time.NewTicker(electionInterval)
+	defer ticker.Stop()
+
+	var term *leadership
+	for {
+		if term == nil {
+			term = a.lead(ctx)
+		} else if err := term.lock.Check(ctx); err != nil && ctx.Err() == nil {
+			a.Errorf("leadership lost err=%q", err)
+			a.resign(ctx, term)
+			term = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if term != nil {
+				a.resign(ctx, term)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead takes the leader lock and starts jobs of the leader, nil is returned if the lock is held by another replica.
+func (a *App) lead(ctx context.Context) *leadership {
+	lock, err := a.db.TryLock(ctx, leaderLock)
+	if err != nil {
+		a.Errorf("taking leader lock err=%q", err)
+		return nil
+	} else if lock == nil {
+		return nil
+	}
+
+	term := &leadership{lock: lock}
+	lctx, cancel := context.WithCancel(ctx)
+	term.cancel = cancel
+	a.setLeader(true)
+	a.Printf("leadership taken")
+
+	if err = a.leadUpdates(lctx); err != nil {
+		a.Errorf("starting updates err=%q", err)
+		a.resign(ctx, term)
+		return nil
+	}
+
+	run := func(name string, fn func(ctx context.Context)) {
+		term.jobs.Add(1)
+		a.goWorker(lctx, name, func(ctx context.Context) {
+			defer term.jobs.Done()
+			fn(ctx)
+		})
+	}
+
+	if !a.isWebhook() {
+		run("poller", a.b.Start)
+	}
+	run("replay", func(ctx context.Context) { a.bm.ReplayUpdates(ctx, a.b) })
+	run("notifier", func(ctx context.Context) { a.bm.RunNotifier(ctx, a.b) })
+	run("outbox", func(ctx context.Context) { a.bm.RunOutbox(ctx, a.b) })
+
+	return term
+}
+
+// resign stops jobs of the leader and releases the lock, so another replica could take over.
+func (a *App) resign(ctx context.Context, term *leadership) {
+	term.cancel()
+	term.jobs.Wait()
+
+	if err := term.lock.Release(botsrv.WithoutCancel(ctx)); err != nil {
+		a.Errorf("releasing leader lock err=%q", err)
+	}
+
+	a.setLeader(false)
+	a.Printf("leadership released")
+}
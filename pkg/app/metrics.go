@@ -1,7 +1,6 @@
 package app
 
 import (
-	"context"
 	"strconv"
 	"time"
 
@@ -25,10 +24,28 @@ func (a *App) registerMetrics() {
 
 	prometheus.MustRegister(statLogEvents)
 
+	a.statFormRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: a.appName,
+		Subsystem: "forms",
+		Name:      "rejected_total",
+		Help:      "Rejected form webhooks by reason.",
+	}, []string{"reason"})
+
+	prometheus.MustRegister(a.statFormRejected)
+
+	a.statLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: a.appName,
+		Subsystem: "leader",
+		Name:      "active",
+		Help:      "Whether the replica is the leader which polls Telegram and runs background jobs.",
+	})
+
+	prometheus.MustRegister(a.statLeader)
+
 	// add db conn metrics
 	metrics := NewConnectionPoolMetrics(a.appName)
 	prometheus.MustRegister(metrics)
-	metrics.ObserveRegularly(context.Background(), a.dbc, "default")
+	metrics.ObserveRegularly(a.ctx, a.dbc, "default")
 
 	a.echo.Use(httpMetrics(a.appName))
 	a.echo.Any("/metrics", echo.WrapHandler(promhttp.Handler()))
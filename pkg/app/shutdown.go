@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"botsrv/pkg/botsrv"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/labstack/echo/v4"
+)
+
+// workers tracks background goroutines and bot handlers which are drained on shutdown.
+type workers struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]int
+
+	// handlers is a number of in-flight bot handlers, new ones are not started once draining is set
+	handlers int
+	draining bool
+	// idle is closed when draining and no handlers are in flight
+	idle chan struct{}
+}
+
+func newWorkers() *workers {
+	return &workers{running: map[string]int{}}
+}
+
+// startHandler counts the bot handler as in-flight, false is returned if the workers are being drained.
+func (w *workers) startHandler() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.draining {
+		return false
+	}
+
+	w.handlers++
+	return true
+}
+
+// finishHandler counts the bot handler as finished.
+func (w *workers) finishHandler() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.handlers--; w.draining && w.handlers == 0 {
+		close(w.idle)
+	}
+}
+
+// goWorker runs fn with the context in a tracked goroutine.
+func (a *App) goWorker(ctx context.Context, name string, fn func(ctx context.Context)) {
+	w := a.workers
+	w.mu.Lock()
+	w.running[name]++
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			w.mu.Lock()
+			if w.running[name]--; w.running[name] == 0 {
+				delete(w.running, name)
+			}
+			w.mu.Unlock()
+			w.wg.Done()
+		}()
+
+		fn(ctx)
+	}()
+}
+
+// trackHandler is the bot middleware that counts in-flight handlers, they are finished on shutdown
+// with the context which is not cancelled with the root one. Updates received after draining started are not handled.
+func (a *App) trackHandler(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		w := a.workers
+		if !w.startHandler() {
+			a.Printf("update %d skipped, shutdown in progress", update.ID)
+			return
+		}
+		defer w.finishHandler()
+
+		next(botsrv.WithoutCancel(ctx), b, update)
+	}
+}
+
+// rejectOnShutdown is the middleware function that makes Telegram resend webhook updates received after shutdown started.
+func (a *App) rejectOnShutdown(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if a.ctx.Err() != nil {
+			return c.NoContent(http.StatusServiceUnavailable)
+		}
+
+		return next(c)
+	}
+}
+
+// drain stops starting bot handlers, waits for in-flight ones and workers until ctx is done and returns description of the abandoned ones.
+func (w *workers) drain(ctx context.Context) string {
+	w.mu.Lock()
+	w.draining = true
+	w.idle = make(chan struct{})
+	if w.handlers == 0 {
+		close(w.idle)
+	}
+	idle := w.idle
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		<-idle
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return ""
+	case <-ctx.Done():
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var names []string
+	for name := range w.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return "handlers=" + strconv.Itoa(w.handlers) + " workers=" + strings.Join(names, ",")
+}
+
+// Shutdown stops receiving updates and HTTP requests, drains bot handlers and background workers within the timeout,
+// stops heartbeat of claimed updates, so abandoned ones are taken over by other replicas, closes the database pool and flushes Sentry events.
+func (a *App) Shutdown(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	a.stopUpdates(ctx)
+	a.cancel()
+
+	if err := a.echo.Shutdown(ctx); err != nil {
+		a.Errorf("shutting down server err=%q", err)
+	}
+
+	if abandoned := a.workers.drain(ctx); abandoned != "" {
+		a.Errorf("shutdown timeout exceeded, abandoned %s", abandoned)
+	} else {
+		a.Printf("shutdown completed, all handlers and workers finished")
+	}
+	a.stopHeartbeat()
+
+	if err := a.dbc.Close(); err != nil {
+		a.Errorf("closing database pool err=%q", err)
+	}
+
+	deadline, _ := ctx.Deadline()
+	sentry.Flush(time.Until(deadline))
+}
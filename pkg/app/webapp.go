@@ -0,0 +1,67 @@
+package app
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"botsrv/pkg/botsrv"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed webapp/index.html
+var webAppHTML string
+
+var webAppTemplate = template.Must(template.New("webapp").Parse(webAppHTML))
+
+type webAppSubmission struct {
+	InitData string            `json:"initData"`
+	Role     string            `json:"role"`
+	Form     map[string]string `json:"form"`
+}
+
+// handleWebApp renders the Mini App registration form.
+func (a *App) handleWebApp(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	return webAppTemplate.Execute(c.Response(), a.bm.WebAppQuestions())
+}
+
+// handleWebAppSubmit verifies init data of the Mini App and sends the form to moderation.
+func (a *App) handleWebAppSubmit(c echo.Context) error {
+	var req webAppSubmission
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Ошибка чтения тела запроса",
+		})
+	}
+
+	var verr botsrv.ValidationError
+	err := a.bm.SubmitWebAppForm(c.Request().Context(), a.b, req.InitData, req.Role, req.Form)
+	if errors.Is(err, botsrv.ErrInvalidInitData) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Не удалось проверить данные Telegram, откройте анкету заново",
+		})
+	} else if errors.As(err, &verr) {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":  "Анкета заполнена некорректно",
+			"fields": verr,
+		})
+	} else if errors.Is(err, botsrv.ErrUnknownRole) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Неизвестная роль",
+		})
+	} else if errors.Is(err, botsrv.ErrBanned) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Вы не можете подать заявку",
+		})
+	} else if err != nil {
+		a.Errorf("webapp form submit err=%q", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Не удалось отправить анкету, попробуйте ещё раз через несколько минут",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "Данные переданы на модерацию"})
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/go-telegram/bot"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderWebhookSecret is a header with the secret token set in setWebhook.
+const HeaderWebhookSecret = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookSecret returns configured secret token of the webhook or the one derived from the bot token.
+func (a *App) webhookSecret() string {
+	if a.cfg.Webhook.SecretToken != "" {
+		return a.cfg.Webhook.SecretToken
+	}
+
+	mac := hmac.New(sha256.New, []byte(a.cfg.Bot.Token))
+	mac.Write([]byte("webhook"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// isWebhook checks if updates are received by the webhook instead of long polling.
+func (a *App) isWebhook() bool {
+	return a.cfg.Webhook.URL != ""
+}
+
+// webhookAuth is the middleware function that rejects updates without valid secret token.
+func (a *App) webhookAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	secret := []byte(a.webhookSecret())
+	return func(c echo.Context) error {
+		if subtle.ConstantTimeCompare([]byte(c.Request().Header.Get(HeaderWebhookSecret)), secret) != 1 {
+			a.Printf("telegram webhook rejected ip=%s", c.RealIP())
+			return c.NoContent(http.StatusUnauthorized)
+		}
+
+		return next(c)
+	}
+}
+
+// startUpdates starts processing of webhook updates, they are received by every replica behind the reverse proxy.
+func (a *App) startUpdates() {
+	if a.isWebhook() {
+		a.goWorker(a.ctx, "webhook", a.b.StartWebhook)
+	}
+}
+
+// leadUpdates sets the webhook or deletes it before polling, it is run by the leader.
+func (a *App) leadUpdates(ctx context.Context) error {
+	if !a.isWebhook() {
+		// getUpdates does not work while the webhook is set
+		_, err := a.b.DeleteWebhook(ctx, &bot.DeleteWebhookParams{})
+		return err
+	}
+
+	if _, err := a.b.SetWebhook(ctx, &bot.SetWebhookParams{
+		URL:            a.cfg.Webhook.URL,
+		AllowedUpdates: allowedUpdates,
+		MaxConnections: a.cfg.Webhook.MaxConnections,
+		SecretToken:    a.webhookSecret(),
+	}); err != nil {
+		return err
+	}
+
+	a.Printf("telegram webhook set url=%s", a.cfg.Webhook.URL)
+	return nil
+}
+
+// stopUpdates deletes the webhook if this replica is the leader, updates are kept by Telegram until the next leader sets it.
+func (a *App) stopUpdates(ctx context.Context) {
+	if !a.isWebhook() || !a.isLeader() {
+		return
+	}
+
+	if _, err := a.b.DeleteWebhook(ctx, &bot.DeleteWebhookParams{}); err != nil {
+		a.Errorf("deleting telegram webhook err=%q", err)
+	}
+}
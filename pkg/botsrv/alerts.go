@@ -0,0 +1,140 @@
+package botsrv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	patternMember = "member"
+	memberRequest = "request"
+	memberKick    = "kick"
+)
+
+// memberData returns signed callback data of the alert button: member_<action>_<tgId>_<signature>.
+func (bm *BotManager) memberData(action string, tgID int64) string {
+	return bm.signData(patternMember, action, strconv.FormatInt(tgID, 10))
+}
+
+func (bm *BotManager) memberAlertKeyboard(tgID int64) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{{Text: "Запросить анкету", CallbackData: bm.memberData(memberRequest, tgID)}},
+			{{Text: "Удалить из чата", CallbackData: bm.memberData(memberKick, tgID)}},
+		},
+	}
+}
+
+// alertUnapproved posts alert to the admin chat about the member who joined the lyceum chat without an approved application.
+// The caller checks that the user has no accepted or joined application, the latest one is only shown in the alert.
+func (bm *BotManager) alertUnapproved(ctx context.Context, b *bot.Bot, upd *models.ChatMemberUpdated, user *models.User) {
+	if user.IsBot {
+		return
+	}
+
+	app, err := bm.ar.LastApplication(ctx, user.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return
+	}
+
+	text := fmt.Sprintf("В чат вступил пользователь без одобренной заявки!\n%s, tgId %d", userName(user), user.ID)
+	if upd.From.ID != user.ID {
+		text += "\nДобавил: " + userName(&upd.From)
+	}
+	if upd.InviteLink != nil {
+		text += "\nСсылка: " + upd.InviteLink.InviteLink
+		if upd.InviteLink.Name != "" {
+			text += " (" + upd.InviteLink.Name + ")"
+		}
+	}
+	if app != nil {
+		text += fmt.Sprintf("\nПоследняя заявка #%d, статус: %s", app.ID, app.Status)
+	}
+
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
+		ChatID:      bm.cfg.AdminChatId,
+		Text:        text,
+		ReplyMarkup: bm.memberAlertKeyboard(user.ID),
+	}))
+}
+
+// MemberAlertHandler handles buttons of the alert about unapproved member and answers the callback query.
+func (bm *BotManager) MemberAlertHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	bm.answerCallback(ctx, b, update, bm.handleMemberAlert(ctx, b, update))
+}
+
+// handleMemberAlert requests application from the member or removes the member from the lyceum chat.
+// It returns text of the toast shown to the moderator.
+func (bm *BotManager) handleMemberAlert(ctx context.Context, b *bot.Bot, update *models.Update) string {
+	parts, err := bm.verifyData(update.CallbackQuery.Data)
+	if err != nil || len(parts) != 3 || parts[0] != patternMember {
+		bm.Errorf("Некорректные данные кнопки %q: %v", update.CallbackQuery.Data, err)
+		return "Некорректная кнопка"
+	}
+
+	tgID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		bm.Errorf("Некорректный tgId=%q: %v", parts[2], err)
+		return errorText
+	}
+
+	actor := &update.CallbackQuery.From
+	msg := update.CallbackQuery.Message.Message
+	switch parts[1] {
+	case memberRequest:
+		bm.send(ctx, sendMessage(&bot.SendMessageParams{
+			ChatID:      tgID,
+			Text:        "Привет! Мы принимаем в чат только по заявкам и не анонимно. Пожалуйста, заполни анкету. Выбери кто ты",
+			ReplyMarkup: bm.startKeyboard(),
+		}))
+
+		bm.editAlert(ctx, b, msg, "Анкета запрошена, модератор: "+userName(actor), bm.memberAlertKeyboard(tgID))
+		return "Анкета запрошена"
+
+	case memberKick:
+		// ban and unban removes the member from the chat but allows to join again after approval
+		if _, err = b.BanChatMember(ctx, &bot.BanChatMemberParams{ChatID: bm.cfg.LyceumChatId, UserID: tgID}); err != nil {
+			bm.Errorf("Ошибка удаления пользователя tgId=%d из чата: %v", tgID, err)
+			return errorText
+		}
+
+		if _, err = b.UnbanChatMember(ctx, &bot.UnbanChatMemberParams{ChatID: bm.cfg.LyceumChatId, UserID: tgID, OnlyIfBanned: true}); err != nil {
+			bm.Errorf("Ошибка разблокировки пользователя tgId=%d: %v", tgID, err)
+		}
+
+		if _, err = bm.ar.AddModerationLog(ctx, &db.ModerationLog{
+			TargetTgID: tgID,
+			ActorTgID:  actor.ID,
+			ActorName:  userName(actor),
+			Action:     db.ModerationActionKick,
+		}); err != nil {
+			bm.Errorf("Ошибка сохранения журнала модерации: %v", err)
+		}
+
+		bm.editAlert(ctx, b, msg, "Удалён из чата, модератор: "+userName(actor), nil)
+		return "Пользователь удалён из чата"
+	}
+
+	return ""
+}
+
+// editAlert appends the note to the alert text and replaces its keyboard.
+func (bm *BotManager) editAlert(ctx context.Context, b *bot.Bot, msg *models.Message, note string, kb *models.InlineKeyboardMarkup) {
+	params := &bot.EditMessageTextParams{
+		ChatID:    msg.Chat.ID,
+		MessageID: msg.ID,
+		Text:      msg.Text + "\n\n" + note,
+	}
+	if kb != nil {
+		params.ReplyMarkup = kb
+	}
+
+	bm.send(ctx, editMessageText(params))
+}
@@ -0,0 +1,101 @@
+package botsrv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const logPageSize = 10
+
+var moderationActionTitles = map[string]string{
+	db.ModerationActionAccept: "принял",
+	db.ModerationActionReject: "отклонил",
+	db.ModerationActionBan:    "заблокировал",
+	db.ModerationActionUndo:   "отменил решение по",
+	db.ModerationActionKick:   "удалил из чата",
+}
+
+// userName returns full name of the Telegram user with username if it is set.
+func userName(u *models.User) string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if u.Username != "" {
+		name += " (@" + u.Username + ")"
+	}
+
+	return name
+}
+
+// newModerationLog returns moderation log entry for the decision made by actor on the application.
+func newModerationLog(app *db.Application, actor *models.User, action, before string) *db.ModerationLog {
+	after := app.Status
+	return &db.ModerationLog{
+		ApplicationID: &app.ID,
+		TargetTgID:    app.TgID,
+		ActorTgID:     actor.ID,
+		ActorName:     userName(actor),
+		Action:        action,
+		StatusBefore:  &before,
+		StatusAfter:   &after,
+		Comment:       app.RejectReason,
+	}
+}
+
+// LogCommandHandler sends page of the moderation log to the admin chat, e.g. "/log 2".
+func (bm *BotManager) LogCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	page := 1
+	if fields := strings.Fields(update.Message.Text); len(fields) > 1 {
+		if p, err := strconv.Atoi(fields[1]); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	list, err := bm.ar.ModerationLogsByFilters(ctx, nil, db.Pager{Page: page, PageSize: logPageSize}, bm.ar.DefaultModerationLogSort())
+	if err != nil {
+		bm.Errorf("Ошибка получения журнала модерации: %v", err)
+		return
+	}
+
+	text := fmt.Sprintf("Журнал модерации, страница %d:\n\n", page)
+	if len(list) == 0 {
+		text += "Записей нет."
+	}
+
+	for _, ml := range list {
+		text += formatModerationLog(ml) + "\n"
+	}
+
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
+		ChatID:          update.Message.Chat.ID,
+		MessageThreadID: update.Message.MessageThreadID,
+		Text:            text,
+	}))
+}
+
+func formatModerationLog(ml db.ModerationLog) string {
+	action, ok := moderationActionTitles[ml.Action]
+	if !ok {
+		action = ml.Action
+	}
+
+	res := fmt.Sprintf("%s %s %s", ml.CreatedAt.Format("02.01.2006 15:04"), ml.ActorName, action)
+	if ml.ApplicationID != nil {
+		res += fmt.Sprintf(" заявку #%d", *ml.ApplicationID)
+	}
+	res += fmt.Sprintf(" (tgId %d)", ml.TargetTgID)
+
+	if ml.StatusBefore != nil && ml.StatusAfter != nil {
+		res += fmt.Sprintf(": %s → %s", *ml.StatusBefore, *ml.StatusAfter)
+	}
+	if ml.Comment != nil {
+		res += ", причина: " + *ml.Comment
+	}
+
+	return res
+}
@@ -0,0 +1,128 @@
+package botsrv
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	alreadyProcessedText = "Уже обработано кем-то другим"
+	errorText            = "Произошла ошибка, попробуйте ещё раз"
+	voteCountedText      = "Голос учтён"
+)
+
+// signatureLen is a length of the hex signature appended to callback data, callback data is limited to 64 bytes.
+const signatureLen = 12
+
+var (
+	errAlreadyProcessed = errors.New("application already processed")
+	errInvalidSignature = errors.New("invalid callback data signature")
+)
+
+// decisionErrorText returns toast text for the error of the moderation decision.
+func decisionErrorText(err error) string {
+	if errors.Is(err, errAlreadyProcessed) {
+		return alreadyProcessedText
+	}
+	return errorText
+}
+
+// sign returns HMAC signature of the callback data payload.
+func (bm *BotManager) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(bm.cfg.callbackSecret()))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))[:signatureLen]
+}
+
+// signData joins parts of the callback data and appends their signature: <part>_<part>_<signature>.
+func (bm *BotManager) signData(parts ...string) string {
+	payload := strings.Join(parts, "_")
+	return payload + "_" + bm.sign(payload)
+}
+
+// verifyData verifies signature of the callback data and returns its parts.
+func (bm *BotManager) verifyData(data string) ([]string, error) {
+	i := strings.LastIndex(data, "_")
+	if i < 0 || !hmac.Equal([]byte(data[i+1:]), []byte(bm.sign(data[:i]))) {
+		return nil, errInvalidSignature
+	}
+
+	return strings.Split(data[:i], "_"), nil
+}
+
+// actionData returns signed callback data of the moderation button: action_<action>_<appId>[_args]_<signature>.
+func (bm *BotManager) actionData(action string, appID int, args ...string) string {
+	return bm.signData(append([]string{patternAction, action, strconv.Itoa(appID)}, args...)...)
+}
+
+// parseActionData verifies signature of the moderation button callback data and returns its action, application ID and arguments.
+func (bm *BotManager) parseActionData(data string) (action string, appID int, args []string, err error) {
+	parts, err := bm.verifyData(data)
+	if err != nil {
+		return "", 0, nil, err
+	} else if len(parts) < 3 || parts[0] != patternAction {
+		return "", 0, nil, errInvalidSignature
+	}
+
+	appID, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	return parts[1], appID, parts[3:], nil
+}
+
+// isModerator checks that the chat is a moderation chat and the Telegram user is its administrator.
+func (bm *BotManager) isModerator(ctx context.Context, b *bot.Bot, chatID, userID int64) bool {
+	if !bm.isModerationChat(chatID) {
+		return false
+	}
+
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{
+		ChatID: chatID,
+		UserID: userID,
+	})
+	if err != nil {
+		bm.Errorf("Ошибка получения участника чата модераторов: %v", err)
+		return false
+	}
+
+	return member.Type == models.ChatMemberTypeOwner || member.Type == models.ChatMemberTypeAdministrator
+}
+
+// answerCallback answers the callback query, non-empty text is shown to the user as a toast.
+func (bm *BotManager) answerCallback(ctx context.Context, b *bot.Bot, update *models.Update, text string) {
+	_, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: update.CallbackQuery.ID,
+		Text:            text,
+	})
+	if err != nil {
+		bm.Errorf("Ошибка ответа на нажатие кнопки: %v", err)
+	}
+}
+
+// ModeratorOnly passes callback queries only from administrators of the moderation chat where the button was pressed.
+func (bm *BotManager) ModeratorOnly(handler bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		if update.CallbackQuery == nil {
+			return
+		}
+
+		msg := update.CallbackQuery.Message.Message
+		if msg == nil || !bm.isModerator(ctx, b, msg.Chat.ID, update.CallbackQuery.From.ID) {
+			bm.Printf("Попытка модерации от пользователя tgId=%d без прав", update.CallbackQuery.From.ID)
+			bm.answerCallback(ctx, b, update, "Недостаточно прав для модерации")
+			return
+		}
+
+		handler(ctx, b, update)
+	}
+}
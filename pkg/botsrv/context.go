@@ -0,0 +1,20 @@
+package botsrv
+
+import (
+	"context"
+	"time"
+)
+
+// uncancelled is a context with values of the parent which is never cancelled.
+type uncancelled struct {
+	context.Context
+}
+
+func (uncancelled) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (uncancelled) Done() <-chan struct{}       { return nil }
+func (uncancelled) Err() error                  { return nil }
+
+// WithoutCancel returns context which is not cancelled with the parent, so work started before shutdown is finished.
+func WithoutCancel(ctx context.Context) context.Context {
+	return uncancelled{ctx}
+}
@@ -0,0 +1,257 @@
+package botsrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// FormModeDialog fills the form by question-by-question dialogue in private chat.
+	FormModeDialog = "dialog"
+	// FormModeGoogle sends applicants to Google Forms which post results to the form webhooks.
+	FormModeGoogle = "google_forms"
+
+	patternDialog = "dialog_"
+	dialogBack    = "back"
+	dialogSkip    = "skip"
+	dialogCancel  = "cancel"
+	dialogSend    = "send"
+)
+
+// question is a step of the registration dialogue.
+type question struct {
+	Field    string // json field of the form
+	Text     string
+	Optional bool
+	Validate func(string) error
+}
+
+// dialogKeyboard returns keyboard of the dialogue step.
+func dialogKeyboard(step int, q *question) *models.InlineKeyboardMarkup {
+	var row []models.InlineKeyboardButton
+	if step > 0 {
+		row = append(row, models.InlineKeyboardButton{Text: "Назад", CallbackData: patternDialog + dialogBack})
+	}
+	if q == nil {
+		row = append(row, models.InlineKeyboardButton{Text: "Отправить на модерацию", CallbackData: patternDialog + dialogSend})
+	} else if q.Optional {
+		row = append(row, models.InlineKeyboardButton{Text: "Пропустить", CallbackData: patternDialog + dialogSkip})
+	}
+
+	rows := [][]models.InlineKeyboardButton{{{Text: "Отменить", CallbackData: patternDialog + dialogCancel}}}
+	if len(row) > 0 {
+		rows = append([][]models.InlineKeyboardButton{row}, rows...)
+	}
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// dialogAnswers returns answers stored in the conversation.
+func dialogAnswers(c *db.Conversation) (map[string]string, error) {
+	answers := map[string]string{}
+	if len(c.Form) == 0 {
+		return answers, nil
+	}
+
+	err := json.Unmarshal(c.Form, &answers)
+	return answers, err
+}
+
+// dialogForm builds the form of the role from the dialogue answers and the Telegram user.
+func dialogForm(c *db.Conversation, user *models.User) (json.RawMessage, error) {
+	answers, err := dialogAnswers(c)
+	if err != nil {
+		return nil, err
+	}
+
+	answers["tgId"] = strconv.FormatInt(user.ID, 10)
+	answers["nickname"] = user.Username
+
+	return json.Marshal(answers)
+}
+
+// startDialog starts registration dialogue of the role, previous dialogue of the user is discarded.
+func (bm *BotManager) startDialog(ctx context.Context, b *bot.Bot, chatID, tgID int64, role string) {
+	c, err := bm.ar.ConversationByTgID(ctx, tgID)
+	if err != nil {
+		bm.Errorf("Ошибка получения диалога tgId=%d: %v", tgID, err)
+		return
+	} else if c == nil {
+		c = &db.Conversation{TgID: tgID}
+	}
+
+	c.Role, c.Step, c.Form = role, 0, json.RawMessage(`{}`)
+	if _, err = bm.ar.SaveConversation(ctx, c); err != nil {
+		bm.Errorf("Ошибка сохранения диалога tgId=%d: %v", tgID, err)
+		return
+	}
+
+	bm.sendDialogStep(ctx, b, chatID, c, "Ответь, пожалуйста, на несколько вопросов о себе. Мы принимаем в чат только по заявкам и не анонимно.\n\n")
+}
+
+// sendDialogStep sends current question of the dialogue or the filled form for confirmation.
+func (bm *BotManager) sendDialogStep(ctx context.Context, b *bot.Bot, chatID int64, c *db.Conversation, prefix string) {
+	questions := bm.questions(c.Role)
+	params := &bot.SendMessageParams{ChatID: chatID}
+	if c.Step < len(questions) {
+		q := &questions[c.Step]
+		params.Text = prefix + q.Text
+		params.ReplyMarkup = dialogKeyboard(c.Step, q)
+	} else {
+		answers, err := dialogAnswers(c)
+		if err != nil {
+			bm.Errorf("Ошибка чтения диалога tgId=%d: %v", c.TgID, err)
+			return
+		}
+
+		raw, _ := json.Marshal(answers)
+		card, err := bm.renderForm(c.Role, raw)
+		if err != nil {
+			bm.Errorf("Ошибка обработки анкеты tgId=%d: %v", c.TgID, err)
+			return
+		}
+
+		params.Text = prefix + "Проверь анкету и отправь её на модерацию:\n\n" + card
+		params.ReplyMarkup = dialogKeyboard(c.Step, nil)
+	}
+
+	bm.send(ctx, sendMessage(params))
+}
+
+// isPrivateText matches text messages in private chats which are not commands.
+func (bm *BotManager) isPrivateText(update *models.Update) bool {
+	return update.Message != nil && update.Message.Chat.Type == models.ChatTypePrivate &&
+		update.Message.Text != "" && !strings.HasPrefix(update.Message.Text, "/")
+}
+
+// DialogAnswerHandler validates and stores the answer to the current question of the registration dialogue.
+func (bm *BotManager) DialogAnswerHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	c, err := bm.ar.ConversationByTgID(ctx, msg.From.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения диалога tgId=%d: %v", msg.From.ID, err)
+		return
+	} else if c == nil || c.Step >= len(bm.questions(c.Role)) {
+		bm.DefaultHandler(ctx, b, update)
+		return
+	}
+
+	q := bm.questions(c.Role)[c.Step]
+	answer := strings.TrimSpace(msg.Text)
+	if err = q.Validate(answer); err != nil {
+		bm.sendDialogStep(ctx, b, msg.Chat.ID, c, err.Error()+"\n\n")
+		return
+	}
+
+	answers, err := dialogAnswers(c)
+	if err != nil {
+		bm.Errorf("Ошибка чтения диалога tgId=%d: %v", c.TgID, err)
+		return
+	}
+
+	answers[q.Field] = answer
+	if c.Form, err = json.Marshal(answers); err != nil {
+		bm.Errorf("Ошибка сохранения диалога tgId=%d: %v", c.TgID, err)
+		return
+	}
+
+	c.Step++
+	if _, err = bm.ar.SaveConversation(ctx, c); err != nil {
+		bm.Errorf("Ошибка сохранения диалога tgId=%d: %v", c.TgID, err)
+		return
+	}
+
+	bm.sendDialogStep(ctx, b, msg.Chat.ID, c, "")
+}
+
+// DialogCallbackHandler handles back, skip, cancel and send buttons of the registration dialogue.
+func (bm *BotManager) DialogCallbackHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	bm.answerCallback(ctx, b, update, bm.handleDialogButton(ctx, b, update))
+}
+
+// handleDialogButton applies the dialogue button and returns text of the toast.
+func (bm *BotManager) handleDialogButton(ctx context.Context, b *bot.Bot, update *models.Update) string {
+	user := &update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Message.Chat.ID
+
+	c, err := bm.ar.ConversationByTgID(ctx, user.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения диалога tgId=%d: %v", user.ID, err)
+		return errorText
+	} else if c == nil {
+		return "Регистрация не начата, напиши " + startCommand
+	}
+
+	questions := bm.questions(c.Role)
+	switch strings.TrimPrefix(update.CallbackQuery.Data, patternDialog) {
+	case dialogBack:
+		if c.Step > 0 {
+			c.Step--
+		}
+
+	case dialogSkip:
+		if c.Step >= len(questions) || !questions[c.Step].Optional {
+			return "Этот вопрос нельзя пропустить"
+		}
+		c.Step++
+
+	case dialogCancel:
+		if _, err = bm.ar.DeleteConversation(ctx, c.ID); err != nil {
+			bm.Errorf("Ошибка удаления диалога tgId=%d: %v", user.ID, err)
+			return errorText
+		}
+
+		bm.sendText(ctx, b, chatID, "Регистрация отменена. Напиши "+startCommand+", чтобы начать заново.")
+		return ""
+
+	case dialogSend:
+		if c.Step < len(questions) {
+			return "Сначала ответь на все вопросы"
+		}
+
+		form, err := dialogForm(c, user)
+		if err != nil {
+			bm.Errorf("Ошибка чтения диалога tgId=%d: %v", user.ID, err)
+			return errorText
+		}
+
+		// answers are kept until the application is stored, so the applicant could send them again
+		text := "Анкета отправлена на модерацию! Мы напишем, как только модераторы её рассмотрят."
+		if err = bm.submitApplication(ctx, b, c.Role, strconv.FormatInt(user.ID, 10), form); errors.Is(err, ErrBanned) {
+			text = "Вы не можете подать заявку. Свяжитесь с " + bm.cfg.contacts() + ", если есть вопросы."
+		} else if err != nil {
+			bm.sendText(ctx, b, chatID, "Не удалось отправить анкету, попробуй ещё раз через несколько минут.")
+			return errorText
+		}
+
+		if _, err = bm.ar.DeleteConversation(ctx, c.ID); err != nil {
+			bm.Errorf("Ошибка удаления диалога tgId=%d: %v", user.ID, err)
+		}
+
+		bm.sendText(ctx, b, chatID, text)
+		return ""
+
+	default:
+		return ""
+	}
+
+	if _, err = bm.ar.SaveConversation(ctx, c); err != nil {
+		bm.Errorf("Ошибка сохранения диалога tgId=%d: %v", user.ID, err)
+		return errorText
+	}
+
+	bm.sendDialogStep(ctx, b, chatID, c, "")
+	return ""
+}
+
+func (bm *BotManager) sendText(ctx context.Context, b *bot.Bot, chatID int64, text string) {
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{ChatID: chatID, Text: text}))
+}
@@ -0,0 +1,103 @@
+package botsrv
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"botsrv/pkg/db"
+)
+
+// memberOf builds the directory entry from the accepted application, answers are taken by field names of the default forms.
+func memberOf(app *db.Application) (*db.Member, error) {
+	form := Form{}
+	if err := json.Unmarshal(app.Form, &form); err != nil {
+		return nil, err
+	}
+
+	m := &db.Member{
+		TgID:          app.TgID,
+		Username:      optional(form["nickname"]),
+		Role:          app.Role,
+		Name:          strings.TrimSpace(form["name"]),
+		Class:         optional(form["class"]),
+		Cities:        splitList(form["cityInfo"]),
+		Universities:  splitList(form["universityInfo"]),
+		Work:          optional(form["workInfo"]),
+		Extra:         optional(form["extraInfo"]),
+		ApplicationID: &app.ID,
+	}
+
+	if year, err := strconv.Atoi(strings.TrimSpace(form["year"])); err == nil {
+		m.Year = &year
+	}
+
+	return m, nil
+}
+
+// optional returns trimmed answer or nil if it is empty.
+func optional(s string) *string {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	return &s
+}
+
+// splitList splits comma-separated answer into trimmed values without empty and repeated ones.
+func splitList(s string) []string {
+	res := []string{}
+	seen := map[string]bool{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if key := strings.ToLower(v); v != "" && !seen[key] {
+			seen[key] = true
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// saveMember adds the applicant of the accepted application to the directory with cities and universities normalised.
+func (bm *BotManager) saveMember(ctx context.Context, dr db.DirectoryRepo, tr db.TermsRepo, app *db.Application) error {
+	m, err := memberOf(app)
+	if err != nil {
+		return err
+	}
+
+	if err = bm.normalizeMember(ctx, tr, m); err != nil {
+		return err
+	}
+
+	_, err = dr.SaveMember(ctx, m)
+	return err
+}
+
+// backfillMembers adds applicants of accepted applications decided before the directory was introduced
+// or whose listing failed, the latest application wins.
+func (bm *BotManager) backfillMembers(ctx context.Context) {
+	list, err := bm.dr.UnlistedApplications(ctx, time.Now().Add(-bm.cfg.undoWindow()))
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок для справочника: %v", err)
+		return
+	}
+
+	added := 0
+	for i := range list {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err = bm.saveMember(WithoutCancel(ctx), bm.dr, bm.tr, &list[i]); err != nil {
+			bm.Errorf("Ошибка добавления в справочник по заявке %d: %v", list[i].ID, err)
+			continue
+		}
+		added++
+	}
+
+	if added > 0 {
+		bm.Printf("В справочник добавлено участников: %d", added)
+	}
+}
@@ -4,65 +4,236 @@ import (
 	"botsrv/pkg/db"
 	"botsrv/pkg/embedlog"
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"strings"
+	"time"
 )
 
 const (
 	startCommand  = "/start"
+	logCommand    = "log"
+	linkCommand   = "link"
 	patternRole   = "role_"
 	patternAction = "action"
 	actionAccept  = "accept"
 	actionReject  = "reject"
-	RoleStudent   = "student"
-	RoleGraduate  = "graduate"
-
-	linkRegisterStudent  = "https://docs.google.com/forms/d/e/1FAIpQLSe_k7fTqytGhSY23jorfXC6HnZy79GR7Acr2JGpKn_UJS3hYg/viewform?usp=pp_url&entry.1409108157=%s&entry.433449939=%d"
-	linkRegisterGraduate = "https://docs.google.com/forms/d/e/1FAIpQLSelgO9-5K_ug_anDOdzf5gbLmetCfgqm2SsZn26Up8QriLRnA/viewform?usp=pp_url&entry.1052289244=%s&entry.1561674486=%d"
+	actionBan     = "ban"
+	actionReason  = "reason"
+	actionCustom  = "custom"
+	actionWait    = "wait"
+	actionBack    = "back"
+	actionUndo    = "undo"
 )
 
-var startReplyMarkup = &models.InlineKeyboardMarkup{
-	InlineKeyboard: [][]models.InlineKeyboardButton{
-		{
-			{Text: "Ученик лицея", CallbackData: patternRole + RoleStudent},
-			{Text: "Выпускник лицея", CallbackData: patternRole + RoleGraduate},
-		},
-	},
+var defaultRejectReasons = []string{
+	"Неверно указан класс или год выпуска",
+	"Не является учеником или выпускником лицея",
+	"Анкета заполнена не полностью",
 }
 
 type Config struct {
-	Token        string
-	AdminChatId  int
-	LyceumChatId int
+	Token         string
+	AdminChatId   int
+	LyceumChatId  int
+	RejectReasons []string
+	Contacts      string
+	// CallbackSecret signs callback data of moderation buttons, bot token is used if empty.
+	CallbackSecret string
+	// Approvals is a number of moderator approvals required to accept the application by role, one by default.
+	// Deprecated: set Approvals of the role in Roles, this setting is used for roles without it.
+	Approvals map[string]int
+	// RejectVotes is a number of moderator rejects that vetoes the application, one by default.
+	RejectVotes int
+	// UndoWindow is a time during which moderators could undo the decision before the applicant is notified, 2 minutes by default.
+	UndoWindow time.Duration
+	// InviteLinkTTL is a lifetime of one-time invite links to the lyceum chat, 24 hours by default.
+	InviteLinkTTL time.Duration
+	// AdmissionMode is a way accepted applicants join the lyceum chat: AdmissionModeInviteLink (default) or AdmissionModeJoinRequest.
+	AdmissionMode string
+	// JoinRequestLink is a link of the lyceum chat which creates join requests, used in AdmissionModeJoinRequest.
+	JoinRequestLink string
+	// FormMode is a way applicants fill the form: FormModeDialog (default), FormModeWebApp or FormModeGoogle.
+	FormMode string
+	// RegistrationTokenTTL is a lifetime of single-use tokens prefilled into form links, 24 hours by default.
+	RegistrationTokenTTL time.Duration
+	// WebAppURL is a public URL of the Mini App form served by the bot server, used in FormModeWebApp.
+	WebAppURL string
+	// Roles are roles of applicants with their forms and moderation settings, student and graduate by default.
+	Roles []RoleConfig
+	// Validation are limits of answers of the forms.
+	Validation FormRules
+	// OutboxMaxAttempts is a number of delivery attempts of outgoing messages before they are dead-lettered, 10 by default.
+	OutboxMaxAttempts int
+	// SearchRoles are roles of members found by /search, graduates by default.
+	SearchRoles []string
+}
+
+// rejectReasons returns configured reject reasons or default ones.
+func (c Config) rejectReasons() []string {
+	if len(c.RejectReasons) == 0 {
+		return defaultRejectReasons
+	}
+	return c.RejectReasons
+}
+
+// contacts returns usernames of people to contact with questions.
+func (c Config) contacts() string {
+	if c.Contacts == "" {
+		return "@kroexov или @mikhailpuminov"
+	}
+	return c.Contacts
+}
+
+// callbackSecret returns key for signing callback data.
+func (c Config) callbackSecret() string {
+	if c.CallbackSecret == "" {
+		return c.Token
+	}
+	return c.CallbackSecret
+}
+
+// rejectVotes returns number of rejects required to reject the application.
+func (c Config) rejectVotes() int {
+	if c.RejectVotes > 0 {
+		return c.RejectVotes
+	}
+	return 1
+}
+
+// undoWindow returns time during which the decision could be undone.
+func (c Config) undoWindow() time.Duration {
+	if c.UndoWindow > 0 {
+		return c.UndoWindow
+	}
+	return 2 * time.Minute
+}
+
+// inviteLinkTTL returns lifetime of invite links.
+func (c Config) inviteLinkTTL() time.Duration {
+	if c.InviteLinkTTL > 0 {
+		return c.InviteLinkTTL
+	}
+	return 24 * time.Hour
+}
+
+// formMode returns configured form mode.
+func (c Config) formMode() string {
+	if c.FormMode == "" {
+		return FormModeDialog
+	}
+	return c.FormMode
+}
+
+// registrationTokenTTL returns lifetime of registration tokens.
+func (c Config) registrationTokenTTL() time.Duration {
+	if c.RegistrationTokenTTL > 0 {
+		return c.RegistrationTokenTTL
+	}
+	return 24 * time.Hour
+}
+
+// searchRoles returns roles of members found by /search.
+func (c Config) searchRoles() []string {
+	if len(c.SearchRoles) > 0 {
+		return c.SearchRoles
+	}
+	return []string{RoleGraduate}
+}
+
+// outboxMaxAttempts returns number of delivery attempts of outgoing messages.
+func (c Config) outboxMaxAttempts() int {
+	if c.OutboxMaxAttempts > 0 {
+		return c.OutboxMaxAttempts
+	}
+	return 10
 }
 
 type BotManager struct {
 	embedlog.Logger
-	dbo db.DB
-	cfg Config
+	dbo        db.DB
+	ar         db.AdmissionRepo
+	or         db.OutboxRepo
+	ur         db.UpdatesRepo
+	dr         db.DirectoryRepo
+	tr         db.TermsRepo
+	sender     *sender
+	cfg        Config
+	roles      []role
+	outboxWake chan struct{}
+	// replica is an owner of claims of updates made by the process
+	replica string
+	claims  *updateClaims
 }
 
-func NewBotManager(logger embedlog.Logger, dbo db.DB, cfg Config) *BotManager {
-	return &BotManager{
-		Logger: logger,
-		dbo:    dbo,
-		cfg:    cfg,
+func NewBotManager(logger embedlog.Logger, dbo db.DB, cfg Config) (*BotManager, error) {
+	roles, err := newRoles(cfg.Roles, cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Approvals) > 0 {
+		logger.Printf("Bot.Approvals устарел, укажите Approvals в Bot.Roles; значения используются для ролей без Approvals: %v", cfg.Approvals)
 	}
+
+	return &BotManager{
+		Logger:     logger,
+		dbo:        dbo,
+		ar:         db.NewAdmissionRepo(dbo),
+		or:         db.NewOutboxRepo(dbo),
+		ur:         db.NewUpdatesRepo(dbo),
+		dr:         db.NewDirectoryRepo(dbo),
+		tr:         db.NewTermsRepo(dbo),
+		sender:     newSender(),
+		cfg:        cfg,
+		roles:      roles,
+		outboxWake: make(chan struct{}, 1),
+		replica:    newReplicaID(),
+		claims:     &updateClaims{ids: map[int64]bool{}},
+	}, nil
 }
 
+// RegisterBotHandlers registers handlers of the bot. Updates are claimed by ProcessOnce after chat filters,
+// so messages of group chats which are not handled are neither claimed nor stored.
 func (bm *BotManager) RegisterBotHandlers(b *bot.Bot) {
-	b.RegisterHandler(bot.HandlerTypeMessageText, startCommand, bot.MatchTypePrefix, bm.PrivateOnly(bm.StartHandler))
-	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternRole, bot.MatchTypePrefix, bm.RoleChooseHandler)
-	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternAction, bot.MatchTypePrefix, bm.ModerationResultHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, startCommand, bot.MatchTypePrefix, bm.PrivateOnly(bm.ProcessOnce(bm.StartHandler)))
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternRole, bot.MatchTypePrefix, bm.ProcessOnce(bm.RoleChooseHandler))
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternDialog, bot.MatchTypePrefix, bm.ProcessOnce(bm.DialogCallbackHandler))
+	b.RegisterHandlerMatchFunc(bm.isPrivateText, bm.ProcessOnce(bm.DialogAnswerHandler))
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternAction, bot.MatchTypePrefix, bm.ProcessOnce(bm.ModeratorOnly(bm.ModerationResultHandler)))
+	b.RegisterHandlerMatchFunc(bm.isAdminReply, bm.ProcessOnce(bm.RejectReasonReplyHandler))
+	b.RegisterHandler(bot.HandlerTypeMessageText, logCommand, bot.MatchTypeCommandStartOnly, bm.AdminChatOnly(bm.ProcessOnce(bm.LogCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeMessageText, termsCommand, bot.MatchTypeCommandStartOnly, bm.AdminChatOnly(bm.ProcessOnce(bm.TermsCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeMessageText, approveCommand, bot.MatchTypeCommandStartOnly, bm.AdminChatOnly(bm.ProcessOnce(bm.ApproveCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeMessageText, mergeCommand, bot.MatchTypeCommandStartOnly, bm.AdminChatOnly(bm.ProcessOnce(bm.MergeCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeMessageText, aliasCommand, bot.MatchTypeCommandStartOnly, bm.AdminChatOnly(bm.ProcessOnce(bm.AliasCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeMessageText, linkCommand, bot.MatchTypeCommandStartOnly, bm.PrivateOnly(bm.ProcessOnce(bm.LinkCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeMessageText, searchCommand, bot.MatchTypeCommandStartOnly, bm.PrivateOnly(bm.ProcessOnce(bm.SearchCommandHandler)))
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternSearch, bot.MatchTypePrefix, bm.ProcessOnce(bm.SearchCallbackHandler))
+	b.RegisterHandlerMatchFunc(bm.isLyceumJoin, bm.ProcessOnce(bm.LyceumJoinHandler))
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, patternMember, bot.MatchTypePrefix, bm.ProcessOnce(bm.ModeratorOnly(bm.MemberAlertHandler)))
+	if bm.cfg.AdmissionMode == AdmissionModeJoinRequest {
+		b.RegisterHandlerMatchFunc(bm.isLyceumJoinRequest, bm.ProcessOnce(bm.JoinRequestHandler))
+	}
 }
 
+// PrivateOnly passes only messages of private chats to the handler.
 func (bm BotManager) PrivateOnly(handler bot.HandlerFunc) bot.HandlerFunc {
 	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
 
-		if update.Message != nil && update.Message.Chat.Type != "private" {
+		if update.Message == nil || update.Message.Chat.Type != "private" {
+			return
+		}
+
+		handler(ctx, b, update)
+	}
+}
+
+func (bm BotManager) AdminChatOnly(handler bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+
+		if update.Message == nil || update.Message.Chat.ID != int64(bm.cfg.AdminChatId) {
 			return
 		}
 
@@ -74,14 +245,10 @@ func (bm *BotManager) DefaultHandler(ctx context.Context, b *bot.Bot, update *mo
 	if update.Message == nil {
 		return
 	}
-	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
 		ChatID: update.Message.Chat.ID,
 		Text:   "Привет! Напиши /start чтобы начать!",
-	})
-	if err != nil {
-		bm.Errorf("%v", err)
-		return
-	}
+	}))
 }
 
 func (bm *BotManager) StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
@@ -99,226 +266,61 @@ func (bm *BotManager) StartHandler(ctx context.Context, b *bot.Bot, update *mode
 	}
 
 	if member != nil && member.Left == nil {
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		bm.send(ctx, sendMessage(&bot.SendMessageParams{
 			ChatID: update.Message.Chat.ID,
 			Text:   "Привет! Ты уже состоишь в группе лицея!",
-		})
-		if err != nil {
-			bm.Errorf("%v", err)
-			return
-		}
+		}))
 		return
 	}
 
-	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
 		ChatID:      update.Message.Chat.ID,
 		Text:        "Привет! Выбери кто ты",
-		ReplyMarkup: startReplyMarkup,
-	})
-	if err != nil {
-		bm.Errorf("%v", err)
-		return
-	}
+		ReplyMarkup: bm.startKeyboard(),
+	}))
 }
 
+// RoleChooseHandler sends the form of the chosen role: starts the dialogue, opens the Mini App or gives the form link.
 func (bm *BotManager) RoleChooseHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	var link string
-
-	parts := strings.Split(update.CallbackQuery.Data, "_")
-	switch parts[1] {
-	case RoleStudent:
-		link = fmt.Sprintf(linkRegisterStudent, update.CallbackQuery.From.Username, update.CallbackQuery.From.ID)
-	case RoleGraduate:
-		link = fmt.Sprintf(linkRegisterGraduate, update.CallbackQuery.From.Username, update.CallbackQuery.From.ID)
-	}
-
-	_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
-		ChatID:    update.CallbackQuery.Message.Message.Chat.ID,
-		MessageID: update.CallbackQuery.Message.Message.ID,
-		Text:      "Пожалуйста, заполни данные о себе в этой форме. Мы принимаем в чат только по заявкам и не анонимно",
-		ReplyMarkup: &models.InlineKeyboardMarkup{
-			InlineKeyboard: [][]models.InlineKeyboardButton{{{
-				Text: "Пройти регистрацию",
-				// todo: + strconv.FormatInt(update.CallbackQuery.From.ID, 10)
-				URL: link,
-			}}}},
-	})
-	if err != nil {
-		bm.Errorf("%v", err)
+	r := bm.role(strings.TrimPrefix(update.CallbackQuery.Data, patternRole))
+	if r == nil {
+		bm.answerCallback(ctx, b, update, "Неизвестная роль, напиши "+startCommand)
 		return
 	}
-}
-
-func (bm *BotManager) ModerationStudent(ctx context.Context, b *bot.Bot, update *models.Update) {
-	var result StudentForm
-	if err := json.Unmarshal([]byte(update.CallbackQuery.Data), &result); err != nil {
-		bm.Errorf("Ошибка парсинга JSON: %v\nДанные: %s", err, update.CallbackQuery.Data)
-
-		if _, err := b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: bm.cfg.AdminChatId,
-			Text:   "Ошибка обработки данных лицеиста",
-		}); err != nil {
-			bm.Errorf("Ошибка отправки сообщения: %v", err)
-		}
 
+	// roles without external form are filled in the dialogue unless the Mini App is used
+	mode := bm.cfg.formMode()
+	if len(r.questions) > 0 && (mode == FormModeDialog || mode != FormModeWebApp && r.FormURL == "") {
+		bm.answerCallback(ctx, b, update, "")
+		bm.startDialog(ctx, b, update.CallbackQuery.Message.Message.Chat.ID, update.CallbackQuery.From.ID, r.Name)
 		return
 	}
 
-	userID := result.TgId
-
-	kb := &models.InlineKeyboardMarkup{
-		InlineKeyboard: [][]models.InlineKeyboardButton{
-			{
-				{
-					Text:         "Принять",
-					CallbackData: strings.Join([]string{patternAction, actionAccept, userID, RoleStudent}, "_"),
-				},
-			},
-			{
-				{
-					Text:         "Отклонить",
-					CallbackData: strings.Join([]string{patternAction, actionReject, userID, RoleStudent}, "_"),
-				},
-			},
-		},
-	}
-
-	res, err := parseStudent(result)
-	if err != nil {
-		bm.Errorf("Ошибка обработки данных лицеиста: %v", err)
-	}
-
-	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      bm.cfg.AdminChatId,
-		Text:        res,
-		ReplyMarkup: kb,
-	})
-	if err != nil {
-		bm.Errorf("Ошибка отправки сообщения: %v", err)
-	}
-}
-
-func (bm *BotManager) ModerationGraduate(ctx context.Context, b *bot.Bot, update *models.Update) {
-	var result GraduateForm
-	if err := json.Unmarshal([]byte(update.CallbackQuery.Data), &result); err != nil {
-		bm.Errorf("Ошибка парсинга JSON: %v\nДанные: %s", err, update.CallbackQuery.Data)
-
-		if _, err := b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: bm.cfg.AdminChatId,
-			Text:   "Ошибка обработки данных выпускника",
-		}); err != nil {
-			bm.Errorf("Ошибка отправки сообщения: %v", err)
-		}
-
-		return
-	}
-
-	userID := result.TgId
-
-	kb := &models.InlineKeyboardMarkup{
-		InlineKeyboard: [][]models.InlineKeyboardButton{
-			{
-				{
-					Text:         "Принять",
-					CallbackData: strings.Join([]string{patternAction, actionAccept, userID, RoleGraduate}, "_"),
-				},
-			},
-			{
-				{
-					Text:         "Отклонить",
-					CallbackData: strings.Join([]string{patternAction, actionReject, userID, RoleGraduate}, "_"),
-				},
-			},
-		},
-	}
-
-	res, err := parseGraduate(result)
-	if err != nil {
-		bm.Errorf("Ошибка обработки данных выпускника: %v", err)
-	}
-
-	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      bm.cfg.AdminChatId,
-		Text:        res,
-		ReplyMarkup: kb,
-	})
-	if err != nil {
-		bm.Errorf("Ошибка отправки сообщения: %v", err)
-	}
-}
-
-func (bm *BotManager) ModerationResultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	parts := strings.Split(update.CallbackQuery.Data, "_")
-	if len(parts) < 4 {
-		bm.Errorf("len parts < 4")
-		return
-	}
-
-	// todo role stuff
-	action, userId, role := parts[1], parts[2], parts[3]
-
-	switch action {
-	case actionAccept:
-		link, err := b.CreateChatInviteLink(ctx, &bot.CreateChatInviteLinkParams{
-			ChatID:      bm.cfg.LyceumChatId,
-			Name:        "Ссылка на вступление в чат с выпускниками",
-			MemberLimit: 1,
-		})
-		if err != nil {
-			bm.Errorf("Ошибка отправки сообщения: %v", err)
-			return
-		}
-
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: userId,
-			Text:   "Ваша заявка была принята! Вот одноразовая ссылка на вступление в группу:\n" + link.InviteLink,
-		})
+	var (
+		text = "Пожалуйста, заполни данные о себе в анкете. Мы принимаем в чат только по заявкам и не анонимно"
+		kb   *models.InlineKeyboardMarkup
+	)
+	if len(r.questions) > 0 && mode == FormModeWebApp {
+		kb = bm.webAppKeyboard(r.Name)
+	} else {
+		token, err := bm.issueRegistrationToken(ctx, update.CallbackQuery.From, r.Name)
 		if err != nil {
-			bm.Errorf("Ошибка отправки сообщения: %v", err)
-		}
-
-		_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
-			Text:        "Заявка принята!\n\n" + update.CallbackQuery.Message.Message.Text,
-			ChatID:      update.CallbackQuery.Message.Message.Chat.ID,
-			MessageID:   update.CallbackQuery.Message.Message.ID,
-			ReplyMarkup: nil,
-		})
-		if err != nil {
-			bm.Errorf("Ошибка исправления сообщения: %v", err)
+			bm.Errorf("Ошибка создания токена регистрации: %v", err)
 			return
 		}
 
-		if role == RoleGraduate {
-			_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-				Text:            strings.ReplaceAll(update.CallbackQuery.Message.Message.Text, "Новая заявка от выпускника!", "Новый выпускник!"),
-				ChatID:          bm.cfg.LyceumChatId,
-				MessageThreadID: 8,
-				ReplyMarkup:     nil,
-			})
-			if err != nil {
-				bm.Errorf("Ошибка исправления сообщения: %v", err)
-				return
-			}
-		}
-
-	case actionReject:
-		_, err := b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: userId,
-			Text:   "Ваша заявка была отклонена! Свяжитесь с @kroexov или @mikhailpuminov, если есть вопросы.",
-		})
-		if err != nil {
-			bm.Errorf("Ошибка отправки сообщения: %v", err)
-		}
-
-		_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
-			Text:        "Заявка отклонена!\n\n" + update.CallbackQuery.Message.Message.Text,
-			ChatID:      update.CallbackQuery.Message.Message.Chat.ID,
-			MessageID:   update.CallbackQuery.Message.Message.ID,
-			ReplyMarkup: nil,
-		})
-		if err != nil {
-			bm.Errorf("Ошибка исправления сообщения: %v", err)
-			return
-		}
+		text = "Пожалуйста, заполни данные о себе в этой форме. Мы принимаем в чат только по заявкам и не анонимно"
+		kb = &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{{
+				Text: "Пройти регистрацию",
+				URL:  fmt.Sprintf(r.FormURL, token),
+			}}}}
 	}
+
+	bm.send(ctx, editMessageText(&bot.EditMessageTextParams{
+		ChatID:      update.CallbackQuery.Message.Message.Chat.ID,
+		MessageID:   update.CallbackQuery.Message.Message.ID,
+		Text:        text,
+		ReplyMarkup: kb,
+	}))
 }
@@ -0,0 +1,221 @@
+package botsrv
+
+import (
+	"context"
+	"time"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// issueInviteLink returns active one-time invite link of the accepted application or creates a new one.
+// It is called in the transaction of ar holding the application lock, so concurrent calls do not create several links.
+// The link created in the transaction which is rolled back is not sent to anyone and expires by itself.
+func (bm *BotManager) issueInviteLink(ctx context.Context, b *bot.Bot, ar db.AdmissionRepo, app *db.Application) (string, error) {
+	link, err := ar.ActiveInviteLink(ctx, app.ID)
+	if err != nil {
+		return "", err
+	} else if link != nil {
+		return link.Link, nil
+	}
+
+	expiresAt := time.Now().Add(bm.cfg.inviteLinkTTL())
+	res, err := b.CreateChatInviteLink(ctx, &bot.CreateChatInviteLinkParams{
+		ChatID:      bm.cfg.LyceumChatId,
+		Name:        "Ссылка на вступление в чат с выпускниками",
+		ExpireDate:  int(expiresAt.Unix()),
+		MemberLimit: 1,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = ar.AddInviteLink(ctx, &db.InviteLink{
+		ApplicationID: app.ID,
+		TgID:          app.TgID,
+		Link:          res.InviteLink,
+		ExpiresAt:     expiresAt,
+	}); err != nil {
+		bm.revokeInviteLink(ctx, b, res.InviteLink)
+		return "", err
+	}
+
+	return res.InviteLink, nil
+}
+
+// revokeInviteLink revokes the invite link which is not tracked by the database.
+func (bm *BotManager) revokeInviteLink(ctx context.Context, b *bot.Bot, link string) {
+	if _, err := b.RevokeChatInviteLink(WithoutCancel(ctx), &bot.RevokeChatInviteLinkParams{
+		ChatID:     bm.cfg.LyceumChatId,
+		InviteLink: link,
+	}); err != nil {
+		bm.Errorf("Ошибка отзыва ссылки %s: %v", link, err)
+	}
+}
+
+// LinkCommandHandler sends a fresh invite link or join request instructions to the applicant whose application was accepted but who has not joined yet.
+// Nothing is issued until the applicant is notified about the decision.
+func (bm *BotManager) LinkCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.From == nil {
+		return
+	}
+
+	app, err := bm.ar.LastAcceptedApplication(ctx, update.Message.From.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return
+	}
+
+	// the decision could be undone until the applicant is notified, so nothing is issued before
+	text := "У вас нет принятой заявки. Напишите /start, чтобы подать заявку."
+	if app != nil && app.NotifiedAt == nil {
+		text = "Ваша заявка принята, ссылка на вступление придёт в течение нескольких минут."
+	} else if app != nil {
+		err = bm.dbo.RunInLock(ctx, applicationLock(app.ID), func(tx *pg.Tx) (err error) {
+			text, err = bm.joinText(ctx, b, bm.ar.WithTransaction(tx), app)
+			return err
+		})
+		if err != nil {
+			bm.Errorf("Ошибка создания ссылки для заявки %d: %v", app.ID, err)
+			return
+		}
+	}
+
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
+		ChatID: update.Message.Chat.ID,
+		Text:   text,
+	}))
+}
+
+// isChatMember checks if the chat member status means presence in the chat.
+func isChatMember(m models.ChatMember) bool {
+	switch m.Type {
+	case models.ChatMemberTypeOwner, models.ChatMemberTypeAdministrator, models.ChatMemberTypeMember:
+		return true
+	case models.ChatMemberTypeRestricted:
+		return m.Restricted.IsMember
+	}
+
+	return false
+}
+
+// chatMemberUser returns user of the chat member.
+func chatMemberUser(m models.ChatMember) *models.User {
+	switch m.Type {
+	case models.ChatMemberTypeOwner:
+		return m.Owner.User
+	case models.ChatMemberTypeAdministrator:
+		return &m.Administrator.User
+	case models.ChatMemberTypeMember:
+		return m.Member.User
+	case models.ChatMemberTypeRestricted:
+		return m.Restricted.User
+	case models.ChatMemberTypeLeft:
+		return m.Left.User
+	case models.ChatMemberTypeBanned:
+		return m.Banned.User
+	}
+
+	return nil
+}
+
+// isLyceumJoin matches chat_member updates about users joining the lyceum chat.
+func (bm *BotManager) isLyceumJoin(update *models.Update) bool {
+	return update.ChatMember != nil && update.ChatMember.Chat.ID == int64(bm.cfg.LyceumChatId) &&
+		!isChatMember(update.ChatMember.OldChatMember) && isChatMember(update.ChatMember.NewChatMember)
+}
+
+// LyceumJoinHandler marks the accepted application as joined when the applicant enters the lyceum chat.
+// Admins are alerted about members who joined without an invite link of the bot and were never admitted.
+func (bm *BotManager) LyceumJoinHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	user := chatMemberUser(update.ChatMember.NewChatMember)
+	if user == nil {
+		return
+	}
+
+	var link *db.InviteLink
+	if update.ChatMember.InviteLink != nil {
+		var err error
+		if link, err = bm.ar.InviteLinkByLink(ctx, update.ChatMember.InviteLink.InviteLink); err != nil {
+			bm.Errorf("Ошибка получения ссылки: %v", err)
+			return
+		}
+	}
+
+	var (
+		app *db.Application
+		err error
+	)
+	if link != nil {
+		app, err = bm.ar.ApplicationByID(ctx, link.ApplicationID)
+	} else {
+		// members who were admitted before could come back
+		app, err = bm.ar.LastAdmittedApplication(ctx, user.ID)
+	}
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return
+	} else if app == nil {
+		bm.alertUnapproved(ctx, b, update.ChatMember, user)
+		return
+	}
+
+	err = bm.dbo.RunInLock(ctx, applicationLock(app.ID), func(tx *pg.Tx) error {
+		ar := bm.ar.WithTransaction(tx)
+		if link != nil && link.UsedAt == nil {
+			if _, err := ar.SetInviteLinkUsed(ctx, link); err != nil {
+				return err
+			}
+		}
+
+		cur, err := ar.ApplicationByID(ctx, app.ID)
+		if err != nil {
+			return err
+		} else if cur == nil || cur.Status != db.ApplicationStatusAccepted {
+			return nil
+		}
+
+		_, err = ar.SetApplicationStatus(ctx, cur, db.ApplicationStatusJoined)
+		return err
+	})
+	if err != nil {
+		bm.Errorf("Ошибка сохранения заявки %d: %v", app.ID, err)
+	}
+}
+
+// revokeExpiredLinks revokes unused invite links after TTL and tells applicants how to get a new one.
+func (bm *BotManager) revokeExpiredLinks(ctx context.Context, b *bot.Bot) {
+	list, err := bm.ar.ExpiredInviteLinks(ctx)
+	if err != nil {
+		bm.Errorf("Ошибка получения ссылок: %v", err)
+		return
+	}
+
+	for i := range list {
+		if ctx.Err() != nil {
+			return
+		}
+
+		link, wctx := &list[i], WithoutCancel(ctx)
+		if _, err = b.RevokeChatInviteLink(wctx, &bot.RevokeChatInviteLinkParams{
+			ChatID:     bm.cfg.LyceumChatId,
+			InviteLink: link.Link,
+		}); err != nil {
+			bm.Errorf("Ошибка отзыва ссылки %d: %v", link.ID, err)
+			continue
+		}
+
+		if _, err = bm.ar.SetInviteLinkRevoked(wctx, link); err != nil {
+			bm.Errorf("Ошибка сохранения ссылки %d: %v", link.ID, err)
+			continue
+		}
+
+		bm.send(wctx, sendMessage(&bot.SendMessageParams{
+			ChatID: link.TgID,
+			Text:   "Срок действия ссылки на вступление в группу истёк. Отправьте /" + linkCommand + ", чтобы получить новую.",
+		}))
+	}
+}
@@ -0,0 +1,122 @@
+package botsrv
+
+import (
+	"context"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// AdmissionModeInviteLink sends one-time invite links to accepted applicants.
+	AdmissionModeInviteLink = "invite_link"
+	// AdmissionModeJoinRequest approves join requests of accepted applicants, the lyceum chat must require join requests.
+	AdmissionModeJoinRequest = "join_request"
+)
+
+// joinText returns text with instructions for joining the lyceum chat according to the admission mode.
+// In join request mode pending join request of the applicant is approved right away.
+// It is called in the transaction of ar holding the application lock, so the link is issued or the request is approved once.
+func (bm *BotManager) joinText(ctx context.Context, b *bot.Bot, ar db.AdmissionRepo, app *db.Application) (string, error) {
+	if bm.cfg.AdmissionMode != AdmissionModeJoinRequest {
+		link, err := bm.issueInviteLink(ctx, b, ar, app)
+		if err != nil {
+			return "", err
+		}
+
+		return "Вот одноразовая ссылка на вступление в группу:\n" + link, nil
+	}
+
+	// applicant could have sent join request before the decision, error means there is no such request
+	if ok, err := b.ApproveChatJoinRequest(ctx, &bot.ApproveChatJoinRequestParams{
+		ChatID: bm.cfg.LyceumChatId,
+		UserID: app.TgID,
+	}); err == nil && ok {
+		return "Вы добавлены в группу!", nil
+	}
+
+	return "Отправьте заявку на вступление в группу по ссылке, она будет одобрена автоматически:\n" + bm.cfg.JoinRequestLink, nil
+}
+
+// isAdmitted checks that the applicant of the application is let into the lyceum chat: the decision is final or the applicant has joined.
+func isAdmitted(app *db.Application) bool {
+	return app.Status == db.ApplicationStatusAccepted && app.NotifiedAt != nil || app.Status == db.ApplicationStatusJoined
+}
+
+// approveJoinRequest approves join request of the applicant if the application is admitted, false is returned otherwise.
+// The application is checked under its lock, so the request is approved either here or by the notifier along with the decision.
+func (bm *BotManager) approveJoinRequest(ctx context.Context, b *bot.Bot, app *db.Application) (approved bool, err error) {
+	err = bm.dbo.RunInLock(ctx, applicationLock(app.ID), func(tx *pg.Tx) error {
+		cur, err := bm.ar.WithTransaction(tx).ApplicationByID(ctx, app.ID)
+		if err != nil || cur == nil || !isAdmitted(cur) {
+			return err
+		}
+
+		if _, err = b.ApproveChatJoinRequest(ctx, &bot.ApproveChatJoinRequestParams{
+			ChatID: bm.cfg.LyceumChatId,
+			UserID: cur.TgID,
+		}); err != nil {
+			return err
+		}
+
+		approved = true
+		return nil
+	})
+
+	return approved, err
+}
+
+// isLyceumJoinRequest matches join requests to the lyceum chat.
+func (bm *BotManager) isLyceumJoinRequest(update *models.Update) bool {
+	return update.ChatJoinRequest != nil && update.ChatJoinRequest.Chat.ID == int64(bm.cfg.LyceumChatId)
+}
+
+// JoinRequestHandler approves join requests of accepted applicants and declines requests of rejected or banned ones.
+// Unknown requesters are asked to apply via /start, their requests are left pending until the decision.
+// Requests of applicants accepted within the undo window are left pending too, the notifier approves them once the window is closed.
+func (bm *BotManager) JoinRequestHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	req := update.ChatJoinRequest
+	app, err := bm.ar.LastApplication(ctx, req.From.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return
+	}
+
+	banned, err := bm.ar.IsBanned(ctx, req.From.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return
+	}
+
+	var text string
+	switch {
+	case banned || app != nil && app.Status == db.ApplicationStatusRejected:
+		if _, err = b.DeclineChatJoinRequest(ctx, &bot.DeclineChatJoinRequestParams{
+			ChatID: bm.cfg.LyceumChatId,
+			UserID: req.From.ID,
+		}); err != nil {
+			bm.Errorf("Ошибка отклонения заявки на вступление tgId=%d: %v", req.From.ID, err)
+		}
+		return
+
+	case app != nil && (app.Status == db.ApplicationStatusPending || app.Status == db.ApplicationStatusAccepted || app.Status == db.ApplicationStatusJoined):
+		if approved, err := bm.approveJoinRequest(ctx, b, app); err != nil {
+			bm.Errorf("Ошибка одобрения заявки на вступление tgId=%d: %v", req.From.ID, err)
+			return
+		} else if approved {
+			return
+		}
+		text = "Ваша анкета на рассмотрении. Заявка на вступление будет одобрена, как только модераторы примут анкету."
+
+	default:
+		text = "Привет! Мы принимаем в чат только по заявкам. Напиши " + startCommand + ", чтобы заполнить анкету."
+	}
+
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
+		ChatID: req.UserChatID,
+		Text:   text,
+	}))
+}
@@ -0,0 +1,456 @@
+package botsrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var (
+	// ErrBanned is returned for applications of banned users.
+	ErrBanned = errors.New("applicant is banned")
+	// ErrInvalidPayload is returned for form webhooks which could not be parsed.
+	ErrInvalidPayload = errors.New("invalid form payload")
+)
+
+// SubmitFormResult handles the form posted by the form webhook in the format of the role provider.
+// Telegram user is resolved from the registration token, ErrInvalidToken is returned for unknown, reused or expired tokens.
+// Invalid answers are not sent to moderators: the applicant is asked to fix them and ValidationError is returned.
+// Other errors mean the application was not stored and the webhook should be retried.
+func (bm *BotManager) SubmitFormResult(ctx context.Context, b *bot.Bot, role, contentType string, body []byte) error {
+	r := bm.role(role)
+	if r == nil {
+		return ErrUnknownRole
+	}
+
+	answers, err := parseFormPayload(r.provider(), contentType, body)
+	if err != nil {
+		bm.Errorf("Ошибка парсинга анкеты: %v\nДанные: %s", err, body)
+		bm.sendText(ctx, b, int64(bm.cfg.AdminChatId), "Ошибка обработки данных анкеты")
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	form, unmapped := r.mapAnswers(answers)
+	if len(unmapped) > 0 {
+		bm.Printf("Анкета роли %s содержит несопоставленные поля: %v", role, unmapped)
+		bm.sendText(ctx, b, int64(bm.cfg.AdminChatId), fmt.Sprintf("Анкета роли %s содержит поля без сопоставления: «%s». Добавьте их в Fields роли в конфиге.",
+			role, strings.Join(unmapped, "», «")))
+	}
+
+	// the token is redeemed along with the application, so the webhook could be retried if it was not stored
+	token := form["token"]
+	rt, err := bm.ar.UsableRegistrationToken(ctx, token)
+	if err != nil {
+		bm.Errorf("Ошибка проверки токена регистрации: %v", err)
+		return err
+	} else if rt == nil || rt.Role != role {
+		bm.Printf("Анкета с неизвестным, использованным или просроченным токеном отклонена, role=%s", role)
+		return ErrInvalidToken
+	}
+
+	delete(form, "token")
+	delete(form, "tgId")
+	delete(form, "nickname")
+	if errs := r.fieldErrors(form); len(errs) > 0 {
+		bm.Printf("Анкета tgId=%d роли %s не прошла проверку: %v", rt.TgID, role, errs)
+		bm.sendText(ctx, b, rt.TgID, invalidFormText(errs))
+		return errs
+	}
+
+	form["tgId"] = strconv.FormatInt(rt.TgID, 10)
+	form["nickname"] = rt.Username
+
+	return bm.submitApplication(ctx, b, role, form["tgId"], form, func(tx *pg.Tx) error {
+		if rt, err := bm.ar.WithTransaction(tx).RedeemRegistrationToken(ctx, token); err != nil {
+			return err
+		} else if rt == nil {
+			return ErrInvalidToken
+		}
+		return nil
+	})
+}
+
+// submitApplication stores the form as a new pending application and sends its card to the moderation chat of the role.
+// Previous pending applications of the same user are marked as expired. ErrBanned is returned for banned users.
+// Functions fns are run in the transaction of the application before it is stored.
+func (bm *BotManager) submitApplication(ctx context.Context, b *bot.Bot, role, tgID string, form interface{}, fns ...func(tx *pg.Tx) error) error {
+	userID, err := strconv.ParseInt(tgID, 10, 64)
+	if err != nil {
+		bm.Errorf("Некорректный tgId=%q: %v", tgID, err)
+		return err
+	}
+
+	raw, err := json.Marshal(form)
+	if err != nil {
+		bm.Errorf("Ошибка сериализации анкеты: %v", err)
+		return err
+	}
+
+	if banned, err := bm.ar.IsBanned(ctx, userID); err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return err
+	} else if banned {
+		bm.Printf("Заявка от заблокированного пользователя tgId=%d пропущена", userID)
+		return ErrBanned
+	}
+
+	pending, err := bm.ar.PendingApplications(ctx, userID)
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return err
+	}
+
+	for i := range pending {
+		_ = bm.closeApplication(ctx, b, &pending[i], nil, "", db.ApplicationStatusExpired, "Заявка устарела, пользователь отправил новую.")
+	}
+
+	// cities and universities are replaced by canonical names before the card is rendered, so hashtags match
+	raw = bm.normalizeForm(ctx, role, raw)
+	app := &db.Application{
+		TgID:      userID,
+		Role:      role,
+		Form:      raw,
+		Status:    db.ApplicationStatusPending,
+		UpdatedAt: time.Now(),
+	}
+
+	// the card is queued along with the application, so it is sent only if the application is stored
+	err = bm.dbo.RunInLock(ctx, applicantLock(userID), func(tx *pg.Tx) error {
+		for _, fn := range fns {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+
+		if app, err = bm.ar.WithTransaction(tx).AddApplication(ctx, app); err != nil {
+			return err
+		}
+
+		card, err := bm.applicationCard(app)
+		if err != nil {
+			bm.Errorf("Ошибка обработки заявки %d: %v", app.ID, err)
+		}
+
+		chatID, threadID := bm.moderationChat(role)
+		msg := sendMessage(&bot.SendMessageParams{
+			ChatID:          chatID,
+			MessageThreadID: threadID,
+			Text:            card,
+			ReplyMarkup:     bm.moderationKeyboard(app.ID),
+		}).forApplication(app.ID)
+		msg.kind = outboxKindCard
+
+		return bm.enqueue(ctx, bm.or.WithTransaction(tx), msg)
+	})
+	if errors.Is(err, ErrInvalidToken) {
+		bm.Printf("Анкета с использованным токеном отклонена, tgId=%d", userID)
+		return err
+	} else if err != nil {
+		bm.Errorf("Ошибка сохранения заявки: %v", err)
+		return err
+	}
+
+	bm.wakeOutbox()
+	return nil
+}
+
+// closeApplication sets final status of the application and replaces its card in the admin chat with the given header.
+// Decisions of moderators are recorded to the moderation log and signed with the moderator name on the card.
+// Status is changed under per-application lock, errAlreadyProcessed is returned if the application was changed concurrently.
+func (bm *BotManager) closeApplication(ctx context.Context, b *bot.Bot, app *db.Application, actor *models.User, action, status, header string) error {
+	before := app.Status
+	err := bm.dbo.RunInLock(ctx, applicationLock(app.ID), func(tx *pg.Tx) error {
+		ar := bm.ar.WithTransaction(tx)
+		cur, err := ar.ApplicationByID(ctx, app.ID)
+		if err != nil {
+			return err
+		} else if cur == nil || cur.Status != before {
+			return errAlreadyProcessed
+		}
+
+		// the card could be delivered after the application was loaded
+		app.AdminMessageID = cur.AdminMessageID
+		if _, err := ar.SetApplicationStatus(ctx, app, status); err != nil {
+			return err
+		}
+
+		if actor != nil {
+			if _, err = ar.AddModerationLog(ctx, newModerationLog(app, actor, action, before)); err != nil {
+				return err
+			}
+		}
+
+		if app.AdminMessageID == nil {
+			return nil
+		}
+
+		// decisions of moderators could be undone until the applicant is notified
+		text, kb := header, models.ReplyMarkup(nil)
+		if actor != nil {
+			text += "\nМодератор: " + userName(actor)
+			if isUndoable(status) {
+				kb = bm.undoKeyboard(app.ID)
+			}
+		}
+
+		return bm.enqueue(ctx, bm.or.WithTransaction(tx), editMessageText(&bot.EditMessageTextParams{
+			Text:        text + "\n\n" + bm.renderCard(app, bm.applicationVotes(ctx, app)),
+			ChatID:      bm.cardChat(app),
+			MessageID:   *app.AdminMessageID,
+			ReplyMarkup: kb,
+		}).forApplication(app.ID))
+	})
+	if errors.Is(err, errAlreadyProcessed) {
+		app.Status = before
+		bm.Printf("Заявка %d уже обработана", app.ID)
+		return err
+	} else if err != nil {
+		bm.Errorf("Ошибка сохранения заявки %d: %v", app.ID, err)
+		return err
+	}
+
+	bm.wakeOutbox()
+	return nil
+}
+
+// applicationLock returns name of the advisory lock taken while the application status is changed.
+func applicationLock(appID int) string {
+	return "application:" + strconv.Itoa(appID)
+}
+
+// applicantLock returns name of the advisory lock taken while the application of the Telegram user is stored.
+func applicantLock(tgID int64) string {
+	return "applicant:" + strconv.FormatInt(tgID, 10)
+}
+
+func (bm *BotManager) moderationKeyboard(appID int) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{
+				{
+					Text:         "Принять",
+					CallbackData: bm.actionData(actionAccept, appID),
+				},
+			},
+			{
+				{
+					Text:         "Отклонить",
+					CallbackData: bm.actionData(actionReject, appID),
+				},
+			},
+			{
+				{
+					Text:         "Заблокировать",
+					CallbackData: bm.actionData(actionBan, appID),
+				},
+			},
+		},
+	}
+}
+
+// reasonsKeyboard returns keyboard with reject reasons, custom reason and back buttons.
+func (bm *BotManager) reasonsKeyboard(appID int) *models.InlineKeyboardMarkup {
+	var rows [][]models.InlineKeyboardButton
+	for i, reason := range bm.cfg.rejectReasons() {
+		rows = append(rows, []models.InlineKeyboardButton{{
+			Text:         reason,
+			CallbackData: bm.actionData(actionReason, appID, strconv.Itoa(i)),
+		}})
+	}
+
+	rows = append(rows,
+		[]models.InlineKeyboardButton{{Text: "Своя причина", CallbackData: bm.actionData(actionCustom, appID)}},
+		[]models.InlineKeyboardButton{{Text: "Назад", CallbackData: bm.actionData(actionBack, appID)}},
+	)
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// customReasonKeyboard marks the card as waiting for a reply with custom reject reason.
+func (bm *BotManager) customReasonKeyboard(appID int) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{{Text: "Ответьте на это сообщение текстом причины", CallbackData: bm.actionData(actionWait, appID)}},
+			{{Text: "Назад", CallbackData: bm.actionData(actionBack, appID)}},
+		},
+	}
+}
+
+// waitingReasonAppID returns ID of the application whose card waits for a custom reject reason.
+func (bm *BotManager) waitingReasonAppID(kb *models.InlineKeyboardMarkup) (int, bool) {
+	if kb == nil {
+		return 0, false
+	}
+
+	for _, row := range kb.InlineKeyboard {
+		for _, btn := range row {
+			action, appID, _, err := bm.parseActionData(btn.CallbackData)
+			if err == nil && action == actionWait {
+				return appID, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func (bm *BotManager) setKeyboard(ctx context.Context, b *bot.Bot, msg *models.Message, kb *models.InlineKeyboardMarkup) {
+	bm.send(ctx, editMessageReplyMarkup(&bot.EditMessageReplyMarkupParams{
+		ChatID:      msg.Chat.ID,
+		MessageID:   msg.ID,
+		ReplyMarkup: kb,
+	}))
+}
+
+// pendingApplication returns application by ID if it is still waiting for moderation.
+func (bm *BotManager) pendingApplication(ctx context.Context, appID int) *db.Application {
+	app, err := bm.ar.ApplicationByID(ctx, appID)
+	if err != nil {
+		bm.Errorf("Ошибка получения заявки %d: %v", appID, err)
+		return nil
+	} else if app == nil {
+		bm.Errorf("Заявка %d не найдена", appID)
+		return nil
+	} else if app.Status != db.ApplicationStatusPending {
+		bm.Printf("Заявка %d уже обработана, status=%s", app.ID, app.Status)
+		return nil
+	}
+
+	return app
+}
+
+// rejectApplication votes against the application. Once enough moderators voted against,
+// the application is rejected and the reason is shown on the card, the applicant is notified after the undo window.
+// It returns true if the application was rejected.
+func (bm *BotManager) rejectApplication(ctx context.Context, b *bot.Bot, app *db.Application, actor *models.User, reason string) (bool, error) {
+	if decided, err := bm.voteApplication(ctx, b, app, actor, db.ApplicationVoteReject, &reason); err != nil || !decided {
+		return false, err
+	}
+
+	app.RejectReason = &reason
+	if err := bm.closeApplication(ctx, b, app, actor, db.ModerationActionReject, db.ApplicationStatusRejected, "Заявка отклонена!\nПричина: "+reason); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// isAdminReply matches text replies to application cards waiting for a custom reject reason in moderation chats.
+// Commands sent as replies are left to their handlers.
+func (bm *BotManager) isAdminReply(update *models.Update) bool {
+	msg := update.Message
+	if msg == nil || !bm.isModerationChat(msg.Chat.ID) || msg.ReplyToMessage == nil ||
+		msg.Text == "" || strings.HasPrefix(msg.Text, "/") {
+		return false
+	}
+
+	_, ok := bm.waitingReasonAppID(msg.ReplyToMessage.ReplyMarkup)
+	return ok
+}
+
+// RejectReasonReplyHandler rejects the application with custom reason sent as a reply to its card.
+func (bm *BotManager) RejectReasonReplyHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	appID, ok := bm.waitingReasonAppID(update.Message.ReplyToMessage.ReplyMarkup)
+	if !ok || update.Message.From == nil {
+		return
+	}
+
+	reason := strings.TrimSpace(update.Message.Text)
+	if reason == "" {
+		return
+	}
+
+	if !bm.isModerator(ctx, b, update.Message.Chat.ID, update.Message.From.ID) {
+		bm.Printf("Попытка модерации от пользователя tgId=%d без прав", update.Message.From.ID)
+		return
+	}
+
+	app := bm.pendingApplication(ctx, appID)
+	if app == nil {
+		return
+	}
+
+	_, _ = bm.rejectApplication(ctx, b, app, update.Message.From, reason)
+}
+
+// ModerationResultHandler handles moderation buttons on the application card and answers the callback query.
+func (bm *BotManager) ModerationResultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	bm.answerCallback(ctx, b, update, bm.moderate(ctx, b, update))
+}
+
+// moderate applies moderation button action and returns text of the toast shown to the moderator.
+func (bm *BotManager) moderate(ctx context.Context, b *bot.Bot, update *models.Update) string {
+	action, appID, args, err := bm.parseActionData(update.CallbackQuery.Data)
+	if err != nil {
+		bm.Errorf("Некорректные данные кнопки %q: %v", update.CallbackQuery.Data, err)
+		return "Некорректная кнопка"
+	}
+
+	actor := &update.CallbackQuery.From
+	if action == actionUndo {
+		return bm.undoApplication(ctx, b, appID, actor)
+	}
+
+	app := bm.pendingApplication(ctx, appID)
+	if app == nil {
+		return alreadyProcessedText
+	}
+
+	switch action {
+	case actionAccept:
+		if decided, err := bm.voteApplication(ctx, b, app, actor, db.ApplicationVoteApprove, nil); err != nil {
+			return decisionErrorText(err)
+		} else if !decided {
+			return voteCountedText
+		}
+
+		if err = bm.closeApplication(ctx, b, app, actor, db.ModerationActionAccept, db.ApplicationStatusAccepted, "Заявка принята!"); err != nil {
+			return decisionErrorText(err)
+		}
+
+	case actionReject:
+		bm.setKeyboard(ctx, b, update.CallbackQuery.Message.Message, bm.reasonsKeyboard(app.ID))
+
+	case actionReason:
+		reasons := bm.cfg.rejectReasons()
+		if len(args) < 1 {
+			bm.Errorf("Не указана причина отказа")
+			return errorText
+		}
+
+		i, err := strconv.Atoi(args[0])
+		if err != nil || i < 0 || i >= len(reasons) {
+			bm.Errorf("Некорректная причина отказа: %s", args[0])
+			return errorText
+		}
+
+		if rejected, err := bm.rejectApplication(ctx, b, app, actor, reasons[i]); err != nil {
+			return decisionErrorText(err)
+		} else if !rejected {
+			return voteCountedText
+		}
+
+	case actionCustom:
+		bm.setKeyboard(ctx, b, update.CallbackQuery.Message.Message, bm.customReasonKeyboard(app.ID))
+
+	case actionBan:
+		if err = bm.closeApplication(ctx, b, app, actor, db.ModerationActionBan, db.ApplicationStatusBanned, "Пользователь заблокирован!"); err != nil {
+			return decisionErrorText(err)
+		}
+
+	case actionBack:
+		bm.setKeyboard(ctx, b, update.CallbackQuery.Message.Message, bm.moderationKeyboard(app.ID))
+	}
+
+	return ""
+}
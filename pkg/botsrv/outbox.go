@@ -0,0 +1,367 @@
+package botsrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot"
+)
+
+const (
+	outboxSendMessage            = "sendMessage"
+	outboxEditMessageText        = "editMessageText"
+	outboxEditMessageReplyMarkup = "editMessageReplyMarkup"
+
+	// outboxKindCard marks moderation card, its message ID is stored to the application on delivery.
+	outboxKindCard = "applicationCard"
+	// outboxKindDeadNotice marks notice about dead-lettered message, no notice is sent if it is dead-lettered itself.
+	outboxKindDeadNotice = "deadNotice"
+
+	outboxInterval   = time.Second
+	outboxBatchSize  = 50
+	outboxMinBackoff = 5 * time.Second
+	outboxMaxBackoff = time.Hour
+)
+
+var errUnknownOutboxMethod = errors.New("unknown outbox method")
+
+// outgoing is a Telegram message delivered by the outbox worker.
+type outgoing struct {
+	method        string
+	params        interface{}
+	applicationID *int
+	kind          string
+}
+
+func sendMessage(params *bot.SendMessageParams) outgoing {
+	return outgoing{method: outboxSendMessage, params: params}
+}
+
+func editMessageText(params *bot.EditMessageTextParams) outgoing {
+	return outgoing{method: outboxEditMessageText, params: params}
+}
+
+func editMessageReplyMarkup(params *bot.EditMessageReplyMarkupParams) outgoing {
+	return outgoing{method: outboxEditMessageReplyMarkup, params: params}
+}
+
+// forApplication links the message to the application.
+func (o outgoing) forApplication(appID int) outgoing {
+	o.applicationID = &appID
+	return o
+}
+
+// chatID returns ID of the recipient chat of the message.
+func (o outgoing) chatID() (int64, error) {
+	var chatID interface{}
+	switch p := o.params.(type) {
+	case *bot.SendMessageParams:
+		chatID = p.ChatID
+	case *bot.EditMessageTextParams:
+		chatID = p.ChatID
+	case *bot.EditMessageReplyMarkupParams:
+		chatID = p.ChatID
+	}
+
+	switch v := chatID.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	return 0, fmt.Errorf("unsupported chat id %v", chatID)
+}
+
+// enqueue writes messages to the outbox. Pass repository bound to the transaction to send messages
+// only if the state change is committed.
+func (bm *BotManager) enqueue(ctx context.Context, or db.OutboxRepo, msgs ...outgoing) error {
+	for _, m := range msgs {
+		chatID, err := m.chatID()
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(m.params)
+		if err != nil {
+			return err
+		}
+
+		om := &db.OutboxMessage{
+			Method:        m.method,
+			ChatID:        chatID,
+			Payload:       payload,
+			ApplicationID: m.applicationID,
+			Status:        db.OutboxStatusPending,
+			NextAttemptAt: time.Now(),
+		}
+		if m.kind != "" {
+			om.Kind = &m.kind
+		}
+
+		if _, err = or.AddOutboxMessage(ctx, om); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send queues messages which are not tied to state changes and wakes up the outbox worker, errors are logged.
+func (bm *BotManager) send(ctx context.Context, msgs ...outgoing) {
+	if err := bm.enqueue(ctx, bm.or, msgs...); err != nil {
+		bm.Errorf("Ошибка постановки сообщения в очередь: %v", err)
+		return
+	}
+
+	bm.wakeOutbox()
+}
+
+// wakeOutbox makes the outbox worker deliver queued messages without waiting for the next tick.
+func (bm *BotManager) wakeOutbox() {
+	select {
+	case bm.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// chatDeliveries tracks chats whose messages are being delivered, each chat is delivered independently of others.
+type chatDeliveries struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	busy map[int64]bool
+}
+
+// start marks the chat as being delivered, false is returned if it is already.
+func (d *chatDeliveries) start(chatID int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.busy[chatID] {
+		return false
+	}
+
+	d.busy[chatID] = true
+	d.wg.Add(1)
+	return true
+}
+
+func (d *chatDeliveries) done(chatID int64) {
+	d.mu.Lock()
+	delete(d.busy, chatID)
+	d.mu.Unlock()
+	d.wg.Done()
+}
+
+// chats returns chats being delivered.
+func (d *chatDeliveries) chats() []int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	res := make([]int64, 0, len(d.busy))
+	for chatID := range d.busy {
+		res = append(res, chatID)
+	}
+	return res
+}
+
+// RunOutbox delivers queued messages until ctx is done, messages being sent are finished after that.
+func (bm *BotManager) RunOutbox(ctx context.Context, b *bot.Bot) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	d := &chatDeliveries{busy: map[int64]bool{}}
+	defer d.wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-bm.outboxWake:
+		}
+
+		bm.deliverOutbox(ctx, b, d)
+	}
+}
+
+// deliverOutbox starts delivery of due messages in order of queueing to chats which are not being delivered,
+// so a chat paused by rate limits does not hold others. Chats are served concurrently within rate limits of the sender.
+func (bm *BotManager) deliverOutbox(ctx context.Context, b *bot.Bot, d *chatDeliveries) {
+	list, err := bm.or.DueOutboxMessages(ctx, outboxBatchSize, d.chats())
+	if err != nil {
+		bm.Errorf("Ошибка получения очереди сообщений: %v", err)
+		return
+	}
+
+	var chats []int64
+	byChat := map[int64][]*db.OutboxMessage{}
+	for i := range list {
+		chatID := list[i].ChatID
+		if _, ok := byChat[chatID]; !ok {
+			chats = append(chats, chatID)
+		}
+		byChat[chatID] = append(byChat[chatID], &list[i])
+	}
+
+	for _, chatID := range chats {
+		if !d.start(chatID) {
+			continue
+		}
+
+		go func(chatID int64, msgs []*db.OutboxMessage) {
+			defer d.done(chatID)
+			bm.deliverChat(ctx, b, msgs)
+		}(chatID, byChat[chatID])
+	}
+}
+
+// deliverChat sends messages to the chat one by one until the first failure or ctx is done.
+// Following messages to the chat are not due until the failed one is delivered or dead-lettered.
+func (bm *BotManager) deliverChat(ctx context.Context, b *bot.Bot, msgs []*db.OutboxMessage) {
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// the message is stored as sent even if shutdown started during delivery
+		wctx := WithoutCancel(ctx)
+		if err := bm.deliver(wctx, b, msg); err != nil {
+			bm.failOutboxMessage(wctx, msg, err)
+			return
+		}
+
+		if _, err := bm.or.SetOutboxMessageSent(wctx, msg); err != nil {
+			bm.Errorf("Ошибка сохранения сообщения %d: %v", msg.ID, err)
+		}
+	}
+}
+
+// deliver calls the Telegram method of the queued message.
+func (bm *BotManager) deliver(ctx context.Context, b *bot.Bot, msg *db.OutboxMessage) error {
+	switch msg.Method {
+	case outboxSendMessage:
+		var params bot.SendMessageParams
+		if err := json.Unmarshal(msg.Payload, &params); err != nil {
+			return err
+		}
+		params.ChatID = msg.ChatID
+
+		res, err := bm.sender.SendMessage(ctx, b, msg.ChatID, &params)
+		if err != nil {
+			return err
+		}
+
+		if msg.Kind != nil && *msg.Kind == outboxKindCard && msg.ApplicationID != nil {
+			if _, err = bm.ar.SetApplicationAdminMessage(ctx, &db.Application{ID: *msg.ApplicationID}, res.ID); err != nil {
+				bm.Errorf("Ошибка сохранения заявки %d: %v", *msg.ApplicationID, err)
+			}
+		}
+
+	case outboxEditMessageText:
+		var params bot.EditMessageTextParams
+		if err := json.Unmarshal(msg.Payload, &params); err != nil {
+			return err
+		}
+		params.ChatID = msg.ChatID
+
+		if _, err := bm.sender.EditMessageText(ctx, b, msg.ChatID, &params); err != nil && !isNotModified(err) {
+			return err
+		}
+
+	case outboxEditMessageReplyMarkup:
+		var params bot.EditMessageReplyMarkupParams
+		if err := json.Unmarshal(msg.Payload, &params); err != nil {
+			return err
+		}
+		params.ChatID = msg.ChatID
+
+		if _, err := bm.sender.EditMessageReplyMarkup(ctx, b, msg.ChatID, &params); err != nil && !isNotModified(err) {
+			return err
+		}
+
+	default:
+		return errUnknownOutboxMethod
+	}
+
+	return nil
+}
+
+// isNotModified checks that the edit failed because the message already has the same content.
+func isNotModified(err error) bool {
+	return errors.Is(err, bot.ErrorBadRequest) && strings.Contains(err.Error(), "message is not modified")
+}
+
+// failOutboxMessage schedules the next attempt of the failed message or dead-letters it.
+func (bm *BotManager) failOutboxMessage(ctx context.Context, msg *db.OutboxMessage, err error) {
+	var next *time.Time
+	if delay, ok := bm.retryDelay(err, msg.Attempts+1); ok {
+		t := time.Now().Add(delay)
+		next = &t
+		bm.Printf("Ошибка отправки сообщения %d, попытка %d, повтор через %s: %v", msg.ID, msg.Attempts+1, delay, err)
+	} else {
+		bm.Errorf("Сообщение %d не доставлено после %d попыток: %v", msg.ID, msg.Attempts+1, err)
+	}
+
+	lastError := err.Error()
+	if _, err = bm.or.SetOutboxMessageFailed(ctx, msg, lastError, next); err != nil {
+		bm.Errorf("Ошибка сохранения сообщения %d: %v", msg.ID, err)
+		return
+	}
+
+	if next == nil {
+		bm.noticeDeadMessage(ctx, msg, lastError)
+	}
+}
+
+// noticeDeadMessage posts notice about the dead-lettered message to the admin chat, it could be resent by outbox.Resend RPC method.
+func (bm *BotManager) noticeDeadMessage(ctx context.Context, msg *db.OutboxMessage, lastError string) {
+	if msg.Kind != nil && *msg.Kind == outboxKindDeadNotice {
+		return
+	}
+
+	text := fmt.Sprintf("Сообщение %d (%s) в чат %d не доставлено после %d попыток.\nОшибка: %s", msg.ID, msg.Method, msg.ChatID, msg.Attempts, lastError)
+	if msg.ApplicationID != nil {
+		text += fmt.Sprintf("\nЗаявка #%d", *msg.ApplicationID)
+	}
+
+	notice := sendMessage(&bot.SendMessageParams{
+		ChatID: bm.cfg.AdminChatId,
+		Text:   text + "\n\nПовторить отправку можно методом outbox.Resend.",
+	})
+	notice.kind = outboxKindDeadNotice
+	bm.send(ctx, notice)
+}
+
+// retryDelay returns delay before the next delivery attempt with exponential backoff,
+// Telegram retry_after is honoured. It returns false if the message should be dead-lettered.
+func (bm *BotManager) retryDelay(err error, attempts int) (time.Duration, bool) {
+	if errors.Is(err, bot.ErrorForbidden) || errors.Is(err, bot.ErrorBadRequest) || errors.Is(err, bot.ErrorNotFound) ||
+		errors.Is(err, errUnknownOutboxMethod) || attempts >= bm.cfg.outboxMaxAttempts() {
+		return 0, false
+	}
+
+	var tooMany *bot.TooManyRequestsError
+	if errors.As(err, &tooMany) && tooMany.RetryAfter > 0 {
+		return time.Duration(tooMany.RetryAfter) * time.Second, true
+	}
+
+	delay := outboxMinBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxBackoff {
+		delay = outboxMaxBackoff
+	}
+
+	return delay, true
+}
@@ -0,0 +1,261 @@
+package botsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FormProviderFlat is a flat JSON object of answers by field names posted by our Apps Script, used by default.
+	FormProviderFlat = "flat"
+	// FormProviderGoogle is an onFormSubmit event of Google Apps Script or its namedValues: question title → list of answers.
+	FormProviderGoogle = "google"
+	// FormProviderYandex is a webhook of Yandex Forms: JSON-RPC params or answer data by question slug.
+	FormProviderYandex = "yandex"
+	// FormProviderTally is a FORM_RESPONSE webhook of Tally.
+	FormProviderTally = "tally"
+)
+
+// formAnswer is an answer extracted from the form webhook payload.
+type formAnswer struct {
+	Key   string // identifier of the question in the form tool
+	Label string // title of the question, if provided
+	Value string
+}
+
+// name returns name of the question for reports.
+func (fa formAnswer) name() string {
+	if fa.Label != "" {
+		return fa.Label
+	}
+	return fa.Key
+}
+
+// formProvider extracts answers from the JSON webhook payload of the form tool.
+type formProvider func(body []byte) ([]formAnswer, error)
+
+var formProviders = map[string]formProvider{
+	FormProviderFlat:   parseFlatPayload,
+	FormProviderGoogle: parseGooglePayload,
+	FormProviderYandex: parseYandexPayload,
+	FormProviderTally:  parseTallyPayload,
+}
+
+// parseFormPayload extracts answers from the webhook payload of the provider, urlencoded bodies are accepted from any provider.
+func parseFormPayload(provider, contentType string, body []byte) ([]formAnswer, error) {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+
+		var res []formAnswer
+		for key, vv := range values {
+			res = append(res, formAnswer{Key: key, Value: strings.Join(vv, ", ")})
+		}
+		return sortAnswers(res), nil
+	}
+
+	parse, ok := formProviders[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown form provider %q", provider)
+	}
+
+	return parse(body)
+}
+
+// parseFlatPayload parses {"field": "answer"} object.
+func parseFlatPayload(body []byte) ([]formAnswer, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	return answersOf(payload), nil
+}
+
+// parseGooglePayload parses {"namedValues": {"Question": ["answer"]}} event or bare namedValues object.
+func parseGooglePayload(body []byte) ([]formAnswer, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	if named, ok := payload["namedValues"].(map[string]interface{}); ok {
+		payload = named
+	}
+
+	return answersOf(payload), nil
+}
+
+// parseYandexPayload parses JSON-RPC {"params": {...}} request or {"answer": {"data": {"slug": {"value": ..., "question": ...}}}} answer.
+func parseYandexPayload(body []byte) ([]formAnswer, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	if params, ok := payload["params"].(map[string]interface{}); ok {
+		return answersOf(params), nil
+	}
+
+	if answer, ok := payload["answer"].(map[string]interface{}); ok {
+		payload = answer
+	}
+
+	data, ok := payload["data"].(map[string]interface{})
+	if !ok {
+		return answersOf(payload), nil
+	}
+
+	var res []formAnswer
+	for key, v := range data {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			res = append(res, formAnswer{Key: key, Value: stringify(v)})
+			continue
+		}
+
+		fa := formAnswer{Key: key, Value: stringify(entry["value"])}
+		if q, ok := entry["question"].(map[string]interface{}); ok {
+			if slug, ok := q["slug"].(string); ok && slug != "" {
+				fa.Key = slug
+			}
+			fa.Label = stringify(q["label"])
+		}
+		res = append(res, fa)
+	}
+
+	return sortAnswers(res), nil
+}
+
+// parseTallyPayload parses {"data": {"fields": [{"key", "label", "value", "options"}]}} response.
+func parseTallyPayload(body []byte) ([]formAnswer, error) {
+	var payload struct {
+		Data struct {
+			Fields []struct {
+				Key     string      `json:"key"`
+				Label   string      `json:"label"`
+				Value   interface{} `json:"value"`
+				Options []struct {
+					ID   string `json:"id"`
+					Text string `json:"text"`
+				} `json:"options"`
+			} `json:"fields"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var res []formAnswer
+	for _, f := range payload.Data.Fields {
+		// choice answers are sent as ids of the options
+		if len(f.Options) > 0 {
+			texts := map[string]string{}
+			for _, o := range f.Options {
+				texts[o.ID] = o.Text
+			}
+
+			var ids []interface{}
+			switch v := f.Value.(type) {
+			case []interface{}:
+				ids = v
+			case string:
+				ids = []interface{}{v}
+			}
+
+			var values []string
+			for _, id := range ids {
+				if s, ok := id.(string); ok && texts[s] != "" {
+					values = append(values, texts[s])
+				}
+			}
+			if len(values) > 0 {
+				f.Value = strings.Join(values, ", ")
+			}
+		}
+
+		res = append(res, formAnswer{Key: f.Key, Label: f.Label, Value: stringify(f.Value)})
+	}
+
+	return res, nil
+}
+
+// answersOf converts JSON object to answers keyed by its fields.
+func answersOf(payload map[string]interface{}) []formAnswer {
+	res := make([]formAnswer, 0, len(payload))
+	for key, v := range payload {
+		res = append(res, formAnswer{Key: key, Value: stringify(v)})
+	}
+
+	return sortAnswers(res)
+}
+
+// sortAnswers sorts answers by key to keep reports stable.
+func sortAnswers(list []formAnswer) []formAnswer {
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// stringify converts JSON value of the answer to text: lists are joined with commas,
+// objects are represented by their text, label or value field.
+func stringify(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		// numbers such as phones and years are not formatted in exponent notation
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		var parts []string
+		for _, item := range v {
+			if s := stringify(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		for _, key := range []string{"text", "label", "value", "ru", "en"} {
+			if s, ok := v[key]; ok {
+				return stringify(s)
+			}
+		}
+	}
+
+	return ""
+}
+
+// mapAnswers maps answers of the form tool onto fields of the role form by configured Fields.
+// Answers whose key or title is neither mapped nor a form field are returned as unmapped.
+func (r *role) mapAnswers(answers []formAnswer) (Form, []string) {
+	form := Form{}
+	var unmapped []string
+	for _, fa := range answers {
+		field, ok := r.Fields[fa.Key]
+		if !ok && fa.Label != "" {
+			field, ok = r.Fields[fa.Label]
+		}
+		if !ok && r.known[fa.Key] {
+			field, ok = fa.Key, true
+		}
+
+		if !ok {
+			unmapped = append(unmapped, fa.name())
+		} else if field != "" {
+			// empty target field ignores the answer
+			form[field] = fa.Value
+		}
+	}
+
+	return form, unmapped
+}
@@ -0,0 +1,334 @@
+package botsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	RoleStudent  = "student"
+	RoleGraduate = "graduate"
+)
+
+var ErrUnknownRole = errors.New("unknown role")
+
+// RoleConfig describes a role of applicants: the start button, the form and moderation of applications.
+type RoleConfig struct {
+	// Name is an identifier of the role stored in applications, e.g. "student".
+	Name string
+	// Title is a label of the role button in the start message.
+	Title string
+	// FormURL is a link to the external form with %s placeholder for the registration token, used in FormModeGoogle
+	// or when the role has no questions.
+	FormURL string
+	// Provider is a format of the form webhook payload: flat (default), google, yandex or tally.
+	Provider string
+	// Fields maps keys or titles of questions of the external form onto form fields, e.g. "Имя и фамилия" = "name".
+	// Answers mapped to the empty field are ignored, other unknown answers are reported to the admin chat.
+	Fields map[string]string
+	// Questions are fields of the form asked in FormModeDialog and FormModeWebApp.
+	Questions []QuestionConfig
+	// Card is a text/template of the moderation card, answers are available by field names, e.g. {{.name}}.
+	Card string
+	// Hashtags are fields whose comma-separated values are appended to the card as hashtags.
+	Hashtags []string
+	// Terms maps fields onto dictionary kinds, city or university: comma-separated values are replaced by canonical names
+	// and unknown ones are queued for approval by administrators.
+	Terms map[string]string
+	// Announce is a text/template of the message sent to the lyceum chat when the application is accepted, nothing is sent if empty.
+	Announce string
+	// AnnounceThreadId is a topic of the lyceum chat for announcements.
+	AnnounceThreadId int
+	// AdminChatId is a chat where applications of the role are moderated by its administrators, Bot.AdminChatId by default.
+	AdminChatId int
+	// AdminThreadId is a topic of the admin chat for application cards.
+	AdminThreadId int
+	// Approvals is a number of moderator approvals required to accept the application, Bot.Approvals of the role or one by default.
+	Approvals int
+}
+
+// QuestionConfig is a field of the role form.
+type QuestionConfig struct {
+	// Field is a json field of the form.
+	Field    string
+	Text     string
+	Optional bool
+	// Validator is a name of the answer check: name, student_class, year, class_letter or text (default).
+	Validator string
+}
+
+// role is a role registry entry with compiled questions and templates.
+type role struct {
+	RoleConfig
+	questions []question
+	rules     FormRules
+	known     map[string]bool // fields of the form
+	card      *template.Template
+	announce  *template.Template
+}
+
+// legacyFormFields map answers of the default forms: the registration token is prefilled into the question
+// which held Telegram ID before, so Apps Script of the forms still posts it as tgId.
+var legacyFormFields = map[string]string{"tgId": "token"}
+
+var defaultRoles = []RoleConfig{
+	{
+		Name:    RoleStudent,
+		Title:   "Ученик лицея",
+		FormURL: "https://docs.google.com/forms/d/e/1FAIpQLSe_k7fTqytGhSY23jorfXC6HnZy79GR7Acr2JGpKn_UJS3hYg/viewform?usp=pp_url&entry.433449939=%s",
+		Fields:  legacyFormFields,
+		Questions: []QuestionConfig{
+			{Field: "name", Text: "Как тебя зовут? Напиши имя и фамилию.", Validator: "name"},
+			{Field: "class", Text: "В каком классе ты учишься? Например, 10Б.", Validator: "student_class"},
+		},
+		Card: `Новая заявка от лицеиста!
+
+{{if .name}}Имя: {{.name}} {{end}}
+
+{{if .class}}Класс: {{.class}} {{end}}
+
+{{if .nickname}}Ник: @{{.nickname}} {{end}}`,
+	},
+	{
+		Name:    RoleGraduate,
+		Title:   "Выпускник лицея",
+		FormURL: "https://docs.google.com/forms/d/e/1FAIpQLSelgO9-5K_ug_anDOdzf5gbLmetCfgqm2SsZn26Up8QriLRnA/viewform?usp=pp_url&entry.1561674486=%s",
+		Fields:  legacyFormFields,
+		Questions: []QuestionConfig{
+			{Field: "name", Text: "Как тебя зовут? Напиши имя и фамилию.", Validator: "name"},
+			{Field: "year", Text: "В каком году ты выпустился? Например, 2019.", Validator: "year"},
+			{Field: "class", Text: "Какая буква была у твоего класса? Например, А.", Validator: "class_letter"},
+			{Field: "cityInfo", Text: "В каких городах ты живёшь или часто бываешь? Перечисли через запятую.", Optional: true},
+			{Field: "universityInfo", Text: "Где ты учился или учишься после лицея? Перечисли ВУЗы через запятую.", Optional: true},
+			{Field: "workInfo", Text: "Чем ты занимаешься, где работаешь?", Optional: true},
+			{Field: "extraInfo", Text: "Расскажи дополнительно о себе.", Optional: true},
+		},
+		Card:             "Новая заявка от выпускника!\n\n" + graduateCardBody,
+		Announce:         "Новый выпускник!\n\n" + graduateCardBody,
+		AnnounceThreadId: 8,
+		Hashtags:         []string{"cityInfo", "universityInfo"},
+		Terms:            map[string]string{"cityInfo": db.TermKindCity, "universityInfo": db.TermKindUniversity},
+	},
+}
+
+const graduateCardBody = `{{if .name}}Имя: {{.name}} {{end}}
+
+{{if .year}}Выпуск {{.year}}, {{if .class}}{{.class}} класс{{end}}{{end}}
+
+{{if .cityInfo}}Города: {{.cityInfo}} {{end}}
+
+{{if .universityInfo}}ВУЗы: {{.universityInfo}} {{end}}
+
+{{if .workInfo}}Работа: {{.workInfo}} {{end}}
+
+{{if .extraInfo}}Дополнительно о себе: {{.extraInfo}} {{end}}
+
+{{if .nickname}}Ник: @{{.nickname}} {{end}}`
+
+// newRoles builds the role registry from the config with answers checked by the rules, default roles are used if none configured.
+func newRoles(list []RoleConfig, rules FormRules) ([]role, error) {
+	if len(list) == 0 {
+		list = defaultRoles
+	}
+
+	res := make([]role, 0, len(list))
+	seen := map[string]bool{}
+	for _, rc := range list {
+		if rc.Name == "" || strings.Contains(rc.Name, "_") {
+			return nil, fmt.Errorf("role %q: name must be non-empty and must not contain underscores", rc.Name)
+		} else if seen[rc.Name] {
+			return nil, fmt.Errorf("role %q: duplicate name", rc.Name)
+		} else if len(rc.Questions) == 0 && rc.FormURL == "" {
+			return nil, fmt.Errorf("role %q: either FormURL or Questions must be set", rc.Name)
+		} else if _, ok := formProviders[rc.provider()]; !ok {
+			return nil, fmt.Errorf("role %q: unknown form provider %q", rc.Name, rc.Provider)
+		}
+		seen[rc.Name] = true
+
+		for field, kind := range rc.Terms {
+			if _, ok := termKinds[kind]; !ok {
+				return nil, fmt.Errorf("role %q: unknown dictionary kind %q of field %q", rc.Name, kind, field)
+			}
+		}
+
+		r := role{RoleConfig: rc, rules: rules, known: map[string]bool{"token": true, "tgId": true, "nickname": true}}
+		for _, field := range rc.Fields {
+			r.known[field] = true
+		}
+
+		for _, qc := range rc.Questions {
+			r.known[qc.Field] = true
+			name := qc.Validator
+			if name == "" {
+				name = "text"
+			}
+
+			validate, ok := validators[name]
+			if !ok {
+				return nil, fmt.Errorf("role %q: unknown validator %q of field %q", rc.Name, qc.Validator, qc.Field)
+			}
+
+			r.questions = append(r.questions, question{Field: qc.Field, Text: qc.Text, Optional: qc.Optional, Validate: func(s string) error {
+				return validate(rules, s)
+			}})
+		}
+
+		var err error
+		if r.card, err = template.New(rc.Name).Option("missingkey=zero").Parse(rc.Card); err != nil {
+			return nil, fmt.Errorf("role %q: card template: %w", rc.Name, err)
+		}
+
+		if rc.Announce != "" {
+			if r.announce, err = template.New(rc.Name).Option("missingkey=zero").Parse(rc.Announce); err != nil {
+				return nil, fmt.Errorf("role %q: announce template: %w", rc.Name, err)
+			}
+		}
+
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// provider returns format of the form webhook payload.
+func (rc RoleConfig) provider() string {
+	if rc.Provider == "" {
+		return FormProviderFlat
+	}
+	return rc.Provider
+}
+
+// role returns registry entry of the role or nil.
+func (bm *BotManager) role(name string) *role {
+	for i := range bm.roles {
+		if bm.roles[i].Name == name {
+			return &bm.roles[i]
+		}
+	}
+
+	return nil
+}
+
+// questions returns questions of the role form, nil for unknown roles.
+func (bm *BotManager) questions(name string) []question {
+	if r := bm.role(name); r != nil {
+		return r.questions
+	}
+
+	return nil
+}
+
+// startKeyboard returns keyboard of the start message with a button per role.
+func (bm *BotManager) startKeyboard() *models.InlineKeyboardMarkup {
+	var rows [][]models.InlineKeyboardButton
+	for _, r := range bm.roles {
+		rows = append(rows, []models.InlineKeyboardButton{{Text: r.Title, CallbackData: patternRole + r.Name}})
+	}
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// moderationChat returns chat and topic where applications of the role are moderated.
+func (bm *BotManager) moderationChat(name string) (int, int) {
+	if r := bm.role(name); r != nil && r.AdminChatId != 0 {
+		return r.AdminChatId, r.AdminThreadId
+	} else if r != nil {
+		return bm.cfg.AdminChatId, r.AdminThreadId
+	}
+
+	return bm.cfg.AdminChatId, 0
+}
+
+// cardChat returns chat of the moderation card of the application.
+func (bm *BotManager) cardChat(app *db.Application) int {
+	chatID, _ := bm.moderationChat(app.Role)
+	return chatID
+}
+
+// isModerationChat checks that applications are moderated in the chat.
+func (bm *BotManager) isModerationChat(chatID int64) bool {
+	if chatID == int64(bm.cfg.AdminChatId) {
+		return true
+	}
+
+	for _, r := range bm.roles {
+		if r.AdminChatId != 0 && int64(r.AdminChatId) == chatID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// approvals returns number of approvals required to accept the application of the role.
+func (bm *BotManager) approvals(name string) int {
+	if r := bm.role(name); r != nil && r.Approvals > 0 {
+		return r.Approvals
+	} else if n := bm.cfg.Approvals[name]; n > 0 {
+		return n
+	}
+
+	return 1
+}
+
+// renderForm renders moderation card of the form of the role.
+func (bm *BotManager) renderForm(name string, raw json.RawMessage) (string, error) {
+	r := bm.role(name)
+	if r == nil {
+		return "", fmt.Errorf("%w %q", ErrUnknownRole, name)
+	}
+
+	return r.render(r.card, raw)
+}
+
+// render executes the template of the role with answers of the form and appends hashtags.
+func (r *role) render(tmpl *template.Template, raw json.RawMessage) (string, error) {
+	answers := map[string]string{}
+	if err := json.Unmarshal(raw, &answers); err != nil {
+		return "", err
+	}
+
+	var result bytes.Buffer
+	if err := tmpl.Execute(&result, answers); err != nil {
+		return "", err
+	}
+
+	var hashtags []string
+	for _, field := range r.Hashtags {
+		for _, s := range strings.Split(answers[field], ",") {
+			if tag := hashtag(s); tag != "" {
+				hashtags = append(hashtags, tag)
+			}
+		}
+	}
+
+	if len(hashtags) == 0 {
+		return result.String(), nil
+	}
+
+	return result.String() + "\n" + strings.Join(hashtags, " "), nil
+}
+
+// hashtag returns hashtag of the value or empty string if the value has no letters or digits.
+func hashtag(value string) string {
+	var parts []string
+	for _, part := range strings.Split(KeepAllowedChars(strings.ToLower(strings.TrimSpace(value))), "_") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "#" + strings.Join(parts, "_")
+}
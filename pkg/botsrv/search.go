@@ -0,0 +1,283 @@
+package botsrv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	searchCommand = "search"
+	patternSearch = "search_"
+
+	// searchPageSize is a number of member cards in a message.
+	searchPageSize = 5
+	// searchHeader starts the message with results, the query is read back from it on paging.
+	searchHeader = "Поиск: "
+	// searchWorkLength is a number of runes of work info shown in the card.
+	searchWorkLength = 200
+	// userLinkPrefix starts links to Telegram users by ID, they are used for members without username.
+	userLinkPrefix = "tg://user?id="
+)
+
+// searchKeys maps prefixes of the query words onto filters, e.g. "город:Казань".
+var searchKeys = map[string]string{
+	"город":      "city",
+	"city":       "city",
+	"вуз":        "university",
+	"university": "university",
+	"год":        "year",
+	"year":       "year",
+	"класс":      "class",
+	"class":      "class",
+}
+
+const searchHelp = `Поиск по справочнику выпускников. Примеры:
+/search Казань — по городу, ВУЗу, работе или имени
+/search город:Казань вуз:КФУ
+/search год:2019 класс:А
+/search 2019 — выпускники года`
+
+// parseSearchQuery parses text of the query: words with prefix key start filter values, other words are searched anywhere.
+// Four-digit numbers are treated as graduation years.
+func parseSearchQuery(text string) db.MemberQuery {
+	values := map[string][]string{}
+	key := ""
+	for _, word := range strings.Fields(text) {
+		if i := strings.Index(word, ":"); i > 0 {
+			if k, ok := searchKeys[strings.ToLower(word[:i])]; ok {
+				key, word = k, word[i+1:]
+				if word == "" {
+					continue
+				}
+			}
+		}
+		values[key] = append(values[key], word)
+
+		// year and class are single words, names of cities and universities could have several
+		if key == "year" || key == "class" {
+			key = ""
+		}
+	}
+
+	q := db.MemberQuery{
+		City:       strings.Join(values["city"], " "),
+		University: strings.Join(values["university"], " "),
+		Class:      strings.Join(values["class"], " "),
+	}
+	if year, err := strconv.Atoi(strings.Join(values["year"], " ")); err == nil {
+		q.Year = &year
+	}
+
+	for _, word := range values[""] {
+		if year, err := strconv.Atoi(word); err == nil && len(word) == 4 && q.Year == nil {
+			q.Year = &year
+			continue
+		}
+		q.Words = append(q.Words, word)
+	}
+
+	return q
+}
+
+// isEmptyQuery checks that the query has no filters.
+func isEmptyQuery(q db.MemberQuery) bool {
+	return q.City == "" && q.University == "" && q.Class == "" && q.Year == nil && len(q.Words) == 0
+}
+
+// isLyceumMember checks that the Telegram user is a member of the lyceum chat.
+func (bm *BotManager) isLyceumMember(ctx context.Context, b *bot.Bot, userID int64) (bool, error) {
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{
+		ChatID: bm.cfg.LyceumChatId,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return member != nil && isChatMember(*member), nil
+}
+
+// SearchCommandHandler searches the directory for members of the lyceum chat and sends the first page of results.
+func (bm *BotManager) SearchCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.From == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	if ok, err := bm.isLyceumMember(ctx, b, update.Message.From.ID); err != nil {
+		bm.Errorf("Ошибка получения участника чата лицея: %v", err)
+		return
+	} else if !ok {
+		bm.send(ctx, sendMessage(&bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   "Поиск доступен только участникам группы лицея.",
+		}))
+		return
+	}
+
+	query := strings.Join(strings.Fields(update.Message.Text)[1:], " ")
+	if isEmptyQuery(parseSearchQuery(query)) {
+		bm.send(ctx, sendMessage(&bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   searchHelp,
+		}))
+		return
+	}
+
+	text, kb, err := bm.searchPage(ctx, b, query, 1)
+	if err != nil {
+		bm.Errorf("Ошибка поиска по справочнику: %v", err)
+		return
+	}
+
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ReplyMarkup: kb,
+	}))
+}
+
+// SearchCallbackHandler shows another page of results, the query is taken from the message.
+func (bm *BotManager) SearchCallbackHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.CallbackQuery.Message.Message
+	page, err := strconv.Atoi(strings.TrimPrefix(update.CallbackQuery.Data, patternSearch))
+	if msg == nil || err != nil || page < 1 || !strings.HasPrefix(msg.Text, searchHeader) {
+		bm.answerCallback(ctx, b, update, "Некорректная кнопка")
+		return
+	}
+
+	if ok, err := bm.isLyceumMember(ctx, b, update.CallbackQuery.From.ID); err != nil {
+		bm.Errorf("Ошибка получения участника чата лицея: %v", err)
+		bm.answerCallback(ctx, b, update, errorText)
+		return
+	} else if !ok {
+		bm.answerCallback(ctx, b, update, "Поиск доступен только участникам группы лицея")
+		return
+	}
+
+	query, _, _ := strings.Cut(strings.TrimPrefix(msg.Text, searchHeader), "\n")
+	text, kb, err := bm.searchPage(ctx, b, query, page)
+	if err != nil {
+		bm.Errorf("Ошибка поиска по справочнику: %v", err)
+		bm.answerCallback(ctx, b, update, errorText)
+		return
+	}
+
+	bm.answerCallback(ctx, b, update, "")
+	bm.send(ctx, editMessageText(&bot.EditMessageTextParams{
+		ChatID:      msg.Chat.ID,
+		MessageID:   msg.ID,
+		Text:        text,
+		ReplyMarkup: kb,
+	}))
+}
+
+// searchPage returns text and keyboard of the page of results: member cards of searchable roles, "написать" links and paging buttons.
+// Members without username are linked by Telegram ID if their privacy settings allow it, otherwise the card says how to contact them.
+// City and university of the query are resolved by the dictionary, so aliases find members too.
+func (bm *BotManager) searchPage(ctx context.Context, b *bot.Bot, query string, page int) (string, models.ReplyMarkup, error) {
+	q := parseSearchQuery(query)
+	q.Roles = bm.cfg.searchRoles()
+	if err := bm.resolveTerms(ctx, &q); err != nil {
+		return "", nil, err
+	}
+
+	list, count, err := bm.dr.SearchMembers(ctx, q, db.Pager{Page: page, PageSize: searchPageSize})
+	if err != nil {
+		return "", nil, err
+	}
+
+	text := searchHeader + query + "\n"
+	if count == 0 {
+		return text + "\nНикого не нашлось.", nil, nil
+	}
+
+	pages := (count + searchPageSize - 1) / searchPageSize
+	text += fmt.Sprintf("Найдено: %d, страница %d из %d\n", count, page, pages)
+
+	var rows [][]models.InlineKeyboardButton
+	for i, m := range list {
+		n := (page-1)*searchPageSize + i + 1
+		text += fmt.Sprintf("\n%d. %s\n", n, formatMember(m))
+
+		url := userLinkPrefix + strconv.FormatInt(m.TgID, 10)
+		if m.Username != nil {
+			url = "https://t.me/" + *m.Username
+		} else if !bm.canLinkUser(ctx, b, m.TgID) {
+			text += "Профиль скрыт настройками приватности, связаться можно через " + bm.cfg.contacts() + "\n"
+			continue
+		}
+		rows = append(rows, []models.InlineKeyboardButton{{
+			Text: fmt.Sprintf("Написать: %d. %s", n, m.Name),
+			URL:  url,
+		}})
+	}
+
+	var nav []models.InlineKeyboardButton
+	if page > 1 {
+		nav = append(nav, models.InlineKeyboardButton{Text: "« Назад", CallbackData: patternSearch + strconv.Itoa(page-1)})
+	}
+	if page < pages {
+		nav = append(nav, models.InlineKeyboardButton{Text: "Вперёд »", CallbackData: patternSearch + strconv.Itoa(page+1)})
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	if len(rows) == 0 {
+		return text, nil, nil
+	}
+
+	return text, &models.InlineKeyboardMarkup{InlineKeyboard: rows}, nil
+}
+
+// canLinkUser checks that the message with tg://user link button to the user could be sent:
+// Telegram rejects such buttons if privacy settings of the user forbid links to the account.
+func (bm *BotManager) canLinkUser(ctx context.Context, b *bot.Bot, tgID int64) bool {
+	chat, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: tgID})
+	if err != nil {
+		bm.Printf("Не удалось получить чат пользователя tgId=%d: %v", tgID, err)
+		return false
+	}
+
+	return !chat.HasPrivateForwards
+}
+
+// formatMember returns card of the member in search results.
+func formatMember(m db.Member) string {
+	lines := []string{m.Name}
+	if m.Year != nil {
+		line := fmt.Sprintf("Выпуск %d", *m.Year)
+		if m.Class != nil {
+			line += ", " + *m.Class + " класс"
+		}
+		lines = append(lines, line)
+	} else if m.Class != nil {
+		lines = append(lines, "Класс: "+*m.Class)
+	}
+	if len(m.Cities) > 0 {
+		lines = append(lines, "Города: "+strings.Join(m.Cities, ", "))
+	}
+	if len(m.Universities) > 0 {
+		lines = append(lines, "ВУЗы: "+strings.Join(m.Universities, ", "))
+	}
+	if m.Work != nil {
+		work := *m.Work
+		if utf8.RuneCountInString(work) > searchWorkLength {
+			work = string([]rune(work)[:searchWorkLength]) + "…"
+		}
+		lines = append(lines, "Работа: "+work)
+	}
+	if m.Username != nil {
+		lines = append(lines, "@"+*m.Username)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,168 @@
+package botsrv
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"golang.org/x/time/rate"
+)
+
+// Bot API limits, see https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+const (
+	// globalRate is a number of messages per second the bot could send to all chats.
+	globalRate = 30
+	// privateRate is a number of messages per second to a private chat.
+	privateRate = 1
+	// groupRate is a number of messages per second to a group, 20 per minute.
+	groupRate = rate.Limit(20.0 / 60)
+	// groupBurst is a number of messages sent to a group at once before the rate applies.
+	groupBurst = 3
+
+	// maxBuckets is a number of chat buckets kept before idle ones are dropped.
+	maxBuckets = 10000
+
+	// maxRateRetries is a number of resends of the message rejected with 429 before the error is returned.
+	maxRateRetries = 2
+)
+
+// bucket limits messages to a single chat and could be paused by Telegram retry_after.
+type bucket struct {
+	limiter     *rate.Limiter
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// pause stops sending to the chat until the time.
+func (bk *bucket) pause(until time.Time) {
+	bk.mu.Lock()
+	defer bk.mu.Unlock()
+	if until.After(bk.pausedUntil) {
+		bk.pausedUntil = until
+	}
+}
+
+// wait blocks until the pause is over and a token is available.
+func (bk *bucket) wait(ctx context.Context) error {
+	bk.mu.Lock()
+	delay := time.Until(bk.pausedUntil)
+	bk.mu.Unlock()
+
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+
+	return bk.limiter.Wait(ctx)
+}
+
+// sender sends messages within Bot API limits: calls wait for tokens of the global and per-chat buckets instead of failing.
+// Messages rejected with 429 pause the bucket of the chat for retry_after and are sent again.
+type sender struct {
+	global  *rate.Limiter
+	mu      sync.Mutex
+	buckets map[int64]*bucket
+}
+
+func newSender() *sender {
+	return &sender{
+		global:  rate.NewLimiter(globalRate, globalRate),
+		buckets: map[int64]*bucket{},
+	}
+}
+
+// bucket returns bucket of the chat, groups and channels have negative IDs.
+func (s *sender) bucket(chatID int64) *bucket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bk, ok := s.buckets[chatID]
+	if !ok {
+		if len(s.buckets) >= maxBuckets {
+			s.prune()
+		}
+
+		limiter := rate.NewLimiter(privateRate, privateRate)
+		if chatID < 0 {
+			limiter = rate.NewLimiter(groupRate, groupBurst)
+		}
+		bk = &bucket{limiter: limiter}
+		s.buckets[chatID] = bk
+	}
+
+	return bk
+}
+
+// prune drops buckets which are full and not paused, they are the same as new ones.
+func (s *sender) prune() {
+	now := time.Now()
+	for chatID, bk := range s.buckets {
+		bk.mu.Lock()
+		idle := now.After(bk.pausedUntil) && bk.limiter.TokensAt(now) >= float64(bk.limiter.Burst())
+		bk.mu.Unlock()
+
+		if idle {
+			delete(s.buckets, chatID)
+		}
+	}
+}
+
+// do calls fn to the chat when both buckets allow it.
+func (s *sender) do(ctx context.Context, chatID int64, fn func() error) error {
+	bk := s.bucket(chatID)
+	for i := 0; ; i++ {
+		if err := bk.wait(ctx); err != nil {
+			return err
+		}
+
+		if err := s.global.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		var tooMany *bot.TooManyRequestsError
+		if !errors.As(err, &tooMany) {
+			return err
+		}
+
+		bk.pause(time.Now().Add(time.Duration(tooMany.RetryAfter) * time.Second))
+		if i >= maxRateRetries {
+			return err
+		}
+	}
+}
+
+func (s *sender) SendMessage(ctx context.Context, b *bot.Bot, chatID int64, params *bot.SendMessageParams) (res *models.Message, err error) {
+	err = s.do(ctx, chatID, func() (err error) {
+		res, err = b.SendMessage(ctx, params)
+		return err
+	})
+
+	return res, err
+}
+
+func (s *sender) EditMessageText(ctx context.Context, b *bot.Bot, chatID int64, params *bot.EditMessageTextParams) (res *models.Message, err error) {
+	err = s.do(ctx, chatID, func() (err error) {
+		res, err = b.EditMessageText(ctx, params)
+		return err
+	})
+
+	return res, err
+}
+
+func (s *sender) EditMessageReplyMarkup(ctx context.Context, b *bot.Bot, chatID int64, params *bot.EditMessageReplyMarkupParams) (res *models.Message, err error) {
+	err = s.do(ctx, chatID, func() (err error) {
+		res, err = b.EditMessageReplyMarkup(ctx, params)
+		return err
+	})
+
+	return res, err
+}
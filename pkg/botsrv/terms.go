@@ -0,0 +1,240 @@
+package botsrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	termsCommand   = "terms"
+	approveCommand = "approve"
+	mergeCommand   = "merge"
+	aliasCommand   = "alias"
+)
+
+// termKinds are titles of dictionary kinds.
+var termKinds = map[string]string{
+	db.TermKindCity:       "город",
+	db.TermKindUniversity: "ВУЗ",
+}
+
+const termsHelp = `Команды словаря:
+/approve 12 — подтвердить значение
+/approve 12 Санкт-Петербург — подтвердить и переименовать
+/merge 12 5 — сделать значение 12 синонимом значения 5
+/alias 5 спб — добавить синоним значению 5`
+
+// normalizeForm replaces values of dictionary fields of the role form by canonical names and alerts administrators of unknown values.
+// The form is returned as is if it could not be normalised.
+func (bm *BotManager) normalizeForm(ctx context.Context, name string, raw json.RawMessage) json.RawMessage {
+	r := bm.role(name)
+	if r == nil || len(r.Terms) == 0 {
+		return raw
+	}
+
+	form := Form{}
+	if err := json.Unmarshal(raw, &form); err != nil {
+		bm.Errorf("Ошибка чтения анкеты: %v", err)
+		return raw
+	}
+
+	var added []db.Term
+	for field, kind := range r.Terms {
+		if form[field] == "" {
+			continue
+		}
+
+		names, terms, err := bm.tr.NormalizeTerms(ctx, kind, splitList(form[field]))
+		if err != nil {
+			bm.Errorf("Ошибка нормализации поля %s: %v", field, err)
+			continue
+		}
+
+		form[field] = strings.Join(names, ", ")
+		added = append(added, terms...)
+	}
+
+	res, err := json.Marshal(form)
+	if err != nil {
+		bm.Errorf("Ошибка сериализации анкеты: %v", err)
+		return raw
+	}
+
+	if len(added) > 0 {
+		text := "Новые значения в словаре, подтвердите или объедините их:\n"
+		for _, t := range added {
+			text += formatTerm(t) + "\n"
+		}
+		bm.send(ctx, sendMessage(&bot.SendMessageParams{
+			ChatID: bm.cfg.AdminChatId,
+			Text:   text + "\n" + termsHelp,
+		}))
+	}
+
+	return res
+}
+
+// normalizeMember replaces cities and universities of the member by canonical names.
+func (bm *BotManager) normalizeMember(ctx context.Context, tr db.TermsRepo, m *db.Member) (err error) {
+	if m.Cities, _, err = tr.NormalizeTerms(ctx, db.TermKindCity, m.Cities); err != nil {
+		return err
+	}
+
+	m.Universities, _, err = tr.NormalizeTerms(ctx, db.TermKindUniversity, m.Universities)
+	return err
+}
+
+// resolveTerms replaces city and university of the search query by canonical names if the dictionary knows them.
+func (bm *BotManager) resolveTerms(ctx context.Context, q *db.MemberQuery) error {
+	for kind, value := range map[string]*string{db.TermKindCity: &q.City, db.TermKindUniversity: &q.University} {
+		if *value == "" {
+			continue
+		}
+
+		term, err := bm.tr.TermByAlias(ctx, kind, db.TermKey(*value))
+		if err != nil {
+			return err
+		} else if term != nil {
+			*value = term.Name
+		}
+	}
+
+	return nil
+}
+
+func formatTerm(t db.Term) string {
+	return fmt.Sprintf("%d. %s: %s", t.ID, termKinds[t.Kind], t.Name)
+}
+
+// TermsCommandHandler lists dictionary values waiting for approval.
+func (bm *BotManager) TermsCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	list, err := bm.tr.PendingTerms(ctx)
+	if err != nil {
+		bm.Errorf("Ошибка получения словаря: %v", err)
+		return
+	}
+
+	text := "Новых значений в словаре нет.\n"
+	if len(list) > 0 {
+		text = "Значения, ожидающие подтверждения:\n"
+		for _, t := range list {
+			text += formatTerm(t) + "\n"
+		}
+	}
+
+	bm.replyText(ctx, update, text+"\n"+termsHelp)
+}
+
+// ApproveCommandHandler approves the dictionary value: /approve <id> [name].
+func (bm *BotManager) ApproveCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	args := strings.Fields(update.Message.Text)[1:]
+	id, err := termID(args, 0)
+	if err != nil {
+		bm.replyText(ctx, update, termsHelp)
+		return
+	}
+
+	var term *db.Term
+	err = bm.dbo.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		term, err = bm.tr.WithTransaction(tx).ApproveTerm(ctx, id, strings.Join(args[1:], " "))
+		return err
+	})
+	if err != nil {
+		bm.replyText(ctx, update, bm.termErrorText(err))
+		return
+	}
+
+	bm.replyText(ctx, update, "Значение подтверждено: "+formatTerm(*term))
+}
+
+// MergeCommandHandler makes the dictionary value an alias of another one: /merge <id> <target id>.
+func (bm *BotManager) MergeCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	args := strings.Fields(update.Message.Text)[1:]
+	id, err := termID(args, 0)
+	if err != nil {
+		bm.replyText(ctx, update, termsHelp)
+		return
+	}
+
+	targetID, err := termID(args, 1)
+	if err != nil || targetID == id {
+		bm.replyText(ctx, update, termsHelp)
+		return
+	}
+
+	var term *db.Term
+	err = bm.dbo.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		term, err = bm.tr.WithTransaction(tx).MergeTerm(ctx, id, targetID)
+		return err
+	})
+	if err != nil {
+		bm.replyText(ctx, update, bm.termErrorText(err))
+		return
+	}
+
+	bm.replyText(ctx, update, fmt.Sprintf("Значение %d объединено с %s", id, formatTerm(*term)))
+}
+
+// AliasCommandHandler adds alias of the dictionary value: /alias <id> <alias>.
+func (bm *BotManager) AliasCommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	args := strings.Fields(update.Message.Text)[1:]
+	id, err := termID(args, 0)
+	if err != nil || len(args) < 2 || db.TermKey(strings.Join(args[1:], " ")) == "" {
+		bm.replyText(ctx, update, termsHelp)
+		return
+	}
+
+	alias := strings.Join(args[1:], " ")
+	err = bm.dbo.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		return bm.tr.WithTransaction(tx).AliasTerm(ctx, id, alias)
+	})
+	if err != nil {
+		bm.replyText(ctx, update, bm.termErrorText(err))
+		return
+	}
+
+	bm.replyText(ctx, update, fmt.Sprintf("Синоним «%s» добавлен к значению %d", alias, id))
+}
+
+// termID parses ID of the dictionary value from the command argument.
+func termID(args []string, i int) (int, error) {
+	if len(args) <= i {
+		return 0, strconv.ErrSyntax
+	}
+
+	return strconv.Atoi(strings.TrimPrefix(args[i], "#"))
+}
+
+// termErrorText returns text of the dictionary command error, unexpected errors are logged.
+func (bm *BotManager) termErrorText(err error) string {
+	switch {
+	case errors.Is(err, db.ErrTermNotFound):
+		return "Значение не найдено"
+	case errors.Is(err, db.ErrTermKindMismatch):
+		return "Нельзя объединить значения разных видов"
+	case errors.Is(err, db.ErrTermAliasTaken):
+		return "Синоним уже относится к другому значению, объедините их через /" + mergeCommand
+	}
+
+	bm.Errorf("Ошибка изменения словаря: %v", err)
+	return errorText
+}
+
+// replyText sends the text to the chat and topic of the command.
+func (bm *BotManager) replyText(ctx context.Context, update *models.Update, text string) {
+	bm.send(ctx, sendMessage(&bot.SendMessageParams{
+		ChatID:          update.Message.Chat.ID,
+		MessageThreadID: update.Message.MessageThreadID,
+		Text:            text,
+	}))
+}
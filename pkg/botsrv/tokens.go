@@ -0,0 +1,36 @@
+package botsrv
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot/models"
+)
+
+var ErrInvalidToken = errors.New("invalid registration token")
+
+// issueRegistrationToken returns new single-use token bound to the Telegram user and the role.
+func (bm *BotManager) issueRegistrationToken(ctx context.Context, user models.User, role string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	rt, err := bm.ar.AddRegistrationToken(ctx, &db.RegistrationToken{
+		Token:     hex.EncodeToString(buf),
+		TgID:      user.ID,
+		Username:  user.Username,
+		Role:      role,
+		ExpiresAt: time.Now().Add(bm.cfg.registrationTokenTTL()),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return rt.Token, nil
+}
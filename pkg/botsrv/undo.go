@@ -0,0 +1,241 @@
+package botsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// notifyInterval is a period of checking decisions whose undo window has closed.
+	notifyInterval = 10 * time.Second
+	// backfillInterval is a period of listing accepted applicants missing in the directory.
+	backfillInterval = time.Minute
+)
+
+var errUndoExpired = errors.New("undo window expired")
+
+// isUndoable checks if the decision with the status could be undone by moderators.
+func isUndoable(status string) bool {
+	return status == db.ApplicationStatusAccepted || status == db.ApplicationStatusRejected
+}
+
+func (bm *BotManager) undoKeyboard(appID int) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{{Text: "Отменить", CallbackData: bm.actionData(actionUndo, appID)}},
+		},
+	}
+}
+
+// undoApplication returns decided application back to pending status while the undo window is open.
+// The decisive vote is removed and the card gets the moderation keyboard back.
+func (bm *BotManager) undoApplication(ctx context.Context, b *bot.Bot, appID int, actor *models.User) string {
+	var (
+		app   *db.Application
+		votes []db.ApplicationVote
+	)
+
+	err := bm.dbo.RunInLock(ctx, applicationLock(appID), func(tx *pg.Tx) (err error) {
+		ar := bm.ar.WithTransaction(tx)
+		app, err = ar.ApplicationByID(ctx, appID)
+		if err != nil {
+			return err
+		} else if app == nil || !isUndoable(app.Status) || app.NotifiedAt != nil ||
+			app.DecidedAt == nil || time.Since(*app.DecidedAt) > bm.cfg.undoWindow() {
+			return errUndoExpired
+		}
+
+		// remove the last vote which made the decision
+		vote := db.ApplicationVoteApprove
+		if app.Status == db.ApplicationStatusRejected {
+			vote = db.ApplicationVoteReject
+		}
+
+		if votes, err = ar.ApplicationVotes(ctx, app.ID); err != nil {
+			return err
+		}
+
+		for i := len(votes) - 1; i >= 0; i-- {
+			if votes[i].Vote == vote {
+				if _, err = ar.DeleteApplicationVote(ctx, votes[i].ID); err != nil {
+					return err
+				}
+				votes = append(votes[:i], votes[i+1:]...)
+				break
+			}
+		}
+
+		before := app.Status
+		app.DecidedAt, app.RejectReason = nil, nil
+		if _, err = ar.SetApplicationStatus(ctx, app, db.ApplicationStatusPending); err != nil {
+			return err
+		}
+
+		if _, err = ar.AddModerationLog(ctx, newModerationLog(app, actor, db.ModerationActionUndo, before)); err != nil || app.AdminMessageID == nil {
+			return err
+		}
+
+		return bm.enqueue(ctx, bm.or.WithTransaction(tx), editMessageText(&bot.EditMessageTextParams{
+			Text:        bm.renderCard(app, votes),
+			ChatID:      bm.cardChat(app),
+			MessageID:   *app.AdminMessageID,
+			ReplyMarkup: bm.moderationKeyboard(app.ID),
+		}).forApplication(app.ID))
+	})
+	if errors.Is(err, errUndoExpired) {
+		return "Время для отмены истекло"
+	} else if err != nil {
+		bm.Errorf("Ошибка отмены решения по заявке %d: %v", appID, err)
+		return errorText
+	}
+
+	bm.wakeOutbox()
+	return "Решение отменено"
+}
+
+// RunNotifier notifies applicants about decisions after the undo window is closed,
+// revokes expired invite links and removes old processed updates until ctx is done, the application being processed is finished after that.
+// Accepted applicants missing in the directory are listed on start and periodically, so failed listings are retried.
+func (bm *BotManager) RunNotifier(ctx context.Context, b *bot.Bot) {
+	ticker := time.NewTicker(notifyInterval)
+	defer ticker.Stop()
+
+	backfill := time.NewTicker(backfillInterval)
+	defer backfill.Stop()
+
+	bm.backfillMembers(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bm.notifyDecided(ctx, b)
+			bm.revokeExpiredLinks(ctx, b)
+			bm.cleanupUpdates(ctx)
+		case <-backfill.C:
+			bm.backfillMembers(ctx)
+		}
+	}
+}
+
+// notifyDecided sends notifications for all decisions whose undo window is closed.
+func (bm *BotManager) notifyDecided(ctx context.Context, b *bot.Bot) {
+	list, err := bm.ar.UnnotifiedApplications(ctx, time.Now().Add(-bm.cfg.undoWindow()))
+	if err != nil {
+		bm.Errorf("Ошибка получения заявок: %v", err)
+		return
+	}
+
+	for i := range list {
+		if ctx.Err() != nil {
+			return
+		}
+		bm.notifyApplication(WithoutCancel(ctx), b, &list[i])
+	}
+}
+
+// notifyApplication marks the application as notified under per-application lock and queues the decision to the applicant,
+// then lists accepted applicant in the directory. Applicants missing in the directory are added by backfillMembers.
+// The invite link is issued or the join request is approved under the same lock, so it is done once,
+// and the application stays unnotified and is retried if it failed.
+func (bm *BotManager) notifyApplication(ctx context.Context, b *bot.Bot, app *db.Application) {
+	err := bm.dbo.RunInLock(ctx, applicationLock(app.ID), func(tx *pg.Tx) error {
+		ar := bm.ar.WithTransaction(tx)
+		cur, err := ar.ApplicationByID(ctx, app.ID)
+		if err != nil {
+			return err
+		} else if cur == nil || cur.Status != app.Status || cur.NotifiedAt != nil {
+			return errAlreadyProcessed
+		}
+
+		var msgs []outgoing
+		switch cur.Status {
+		case db.ApplicationStatusAccepted:
+			text, err := bm.joinText(ctx, b, ar, cur)
+			if err != nil {
+				return fmt.Errorf("join text: %w", err)
+			}
+			msgs = bm.acceptedMessages(cur, text)
+		case db.ApplicationStatusRejected:
+			msgs = bm.rejectedMessages(cur)
+		}
+
+		if _, err = ar.SetApplicationNotified(ctx, app); err != nil {
+			return err
+		}
+
+		// remove undo button
+		if cur.AdminMessageID != nil {
+			err = bm.enqueue(ctx, bm.or.WithTransaction(tx), editMessageReplyMarkup(&bot.EditMessageReplyMarkupParams{
+				ChatID:    bm.cardChat(app),
+				MessageID: *cur.AdminMessageID,
+			}).forApplication(app.ID))
+			if err != nil {
+				return err
+			}
+		}
+
+		return bm.enqueue(ctx, bm.or.WithTransaction(tx), msgs...)
+	})
+	if errors.Is(err, errAlreadyProcessed) {
+		return
+	} else if err != nil {
+		bm.Errorf("Ошибка уведомления по заявке %d: %v", app.ID, err)
+		return
+	}
+
+	bm.wakeOutbox()
+
+	// the directory is filled after the decision is final, so its errors do not hold the notification back
+	if app.Status == db.ApplicationStatusAccepted {
+		if err = bm.saveMember(ctx, bm.dr, bm.tr, app); err != nil {
+			bm.Errorf("Ошибка добавления в справочник по заявке %d: %v", app.ID, err)
+		}
+	}
+}
+
+// acceptedMessages returns instructions for joining to the applicant and announcement of the new member in the lyceum chat if the role has one.
+func (bm *BotManager) acceptedMessages(app *db.Application, joinText string) []outgoing {
+	msgs := []outgoing{sendMessage(&bot.SendMessageParams{
+		ChatID: app.TgID,
+		Text:   "Ваша заявка была принята! " + joinText,
+	}).forApplication(app.ID)}
+
+	r := bm.role(app.Role)
+	if r == nil || r.announce == nil {
+		return msgs
+	}
+
+	text, err := r.render(r.announce, app.Form)
+	if err != nil {
+		bm.Errorf("Ошибка обработки заявки %d: %v", app.ID, err)
+		return msgs
+	}
+
+	return append(msgs, sendMessage(&bot.SendMessageParams{
+		Text:            text,
+		ChatID:          bm.cfg.LyceumChatId,
+		MessageThreadID: r.AnnounceThreadId,
+	}).forApplication(app.ID))
+}
+
+// rejectedMessages returns reject reason for the applicant.
+func (bm *BotManager) rejectedMessages(app *db.Application) []outgoing {
+	reason := ""
+	if app.RejectReason != nil {
+		reason = *app.RejectReason
+	}
+
+	return []outgoing{sendMessage(&bot.SendMessageParams{
+		ChatID: app.TgID,
+		Text:   "Ваша заявка была отклонена.\nПричина: " + reason + "\n\nСвяжитесь с " + bm.cfg.contacts() + ", если есть вопросы.",
+	}).forApplication(app.ID)}
+}
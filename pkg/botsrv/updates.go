@@ -0,0 +1,175 @@
+package botsrv
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// processedUpdatesTTL is a time processed updates are kept to skip duplicates, Telegram keeps unconfirmed updates for 24 hours.
+	processedUpdatesTTL = 48 * time.Hour
+	// claimHeartbeatInterval is a period of refreshing claims of updates being processed by the replica.
+	claimHeartbeatInterval = 10 * time.Second
+	// claimTimeout is a time after the last heartbeat the claim is taken over, its owner is considered stopped.
+	claimTimeout = 3 * claimHeartbeatInterval
+)
+
+// updateClaims tracks updates claimed by the replica whose handlers are running.
+type updateClaims struct {
+	mu  sync.Mutex
+	ids map[int64]bool
+}
+
+func (c *updateClaims) add(id int64) {
+	c.mu.Lock()
+	c.ids[id] = true
+	c.mu.Unlock()
+}
+
+func (c *updateClaims) remove(id int64) {
+	c.mu.Lock()
+	delete(c.ids, id)
+	c.mu.Unlock()
+}
+
+// list returns IDs of updates being processed.
+func (c *updateClaims) list() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make([]int64, 0, len(c.ids))
+	for id := range c.ids {
+		res = append(res, id)
+	}
+	return res
+}
+
+// newReplicaID returns unique ID of the process which owns claims of updates.
+func newReplicaID() string {
+	host, _ := os.Hostname()
+	if len(host) > 40 {
+		host = host[:40]
+	}
+
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return host + "-" + hex.EncodeToString(buf)
+}
+
+// ProcessOnce wraps the handler to claim the update in the database before it and mark the update processed after.
+// Duplicate updates are skipped, claims of replicas which stopped heartbeating are taken over as their handlers were interrupted.
+// Handlers are wrapped after chat filters, so only updates the bot acts on are stored.
+func (bm *BotManager) ProcessOnce(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		payload, err := json.Marshal(update)
+		if err != nil {
+			bm.Errorf("Ошибка сериализации обновления %d: %v", update.ID, err)
+			next(ctx, b, update)
+			return
+		}
+
+		pu := &db.ProcessedUpdate{ID: update.ID, Payload: payload, Owner: bm.replica}
+		claimed, err := bm.ur.ClaimUpdate(ctx, pu, time.Now().Add(-claimTimeout))
+		if err != nil {
+			bm.Errorf("Ошибка сохранения обновления %d: %v", update.ID, err)
+		} else if !claimed {
+			bm.Printf("Обновление %d уже обработано, пропущено", update.ID)
+			return
+		}
+
+		if err == nil {
+			bm.claims.add(update.ID)
+			defer bm.claims.remove(update.ID)
+		}
+
+		next(ctx, b, update)
+
+		if err == nil {
+			if _, err = bm.ur.SetUpdateProcessed(ctx, pu); err != nil {
+				bm.Errorf("Ошибка сохранения обновления %d: %v", update.ID, err)
+			}
+		}
+	}
+}
+
+// RunHeartbeat refreshes claims of updates being processed by the replica until ctx is done.
+// It must run on every replica until its handlers are drained, as they are run by webhooks and by the former leader too.
+func (bm *BotManager) RunHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(claimHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := bm.ur.HeartbeatUpdates(ctx, bm.replica, bm.claims.list()); err != nil && ctx.Err() == nil {
+				bm.Errorf("Ошибка продления обработки обновлений: %v", err)
+			}
+		}
+	}
+}
+
+// LastUpdateID returns ID of the latest received update to resume polling from, errors are logged.
+func (bm *BotManager) LastUpdateID(ctx context.Context) int64 {
+	id, err := bm.ur.LastUpdateID(ctx)
+	if err != nil {
+		bm.Errorf("Ошибка получения последнего обновления: %v", err)
+	}
+
+	return id
+}
+
+// ReplayUpdates processes updates whose handlers were interrupted by shutdown or crash of their replica until ctx is done.
+// Updates whose owners stopped heartbeating are checked periodically and claimed again by the handlers.
+func (bm *BotManager) ReplayUpdates(ctx context.Context, b *bot.Bot) {
+	ticker := time.NewTicker(claimTimeout)
+	defer ticker.Stop()
+
+	for {
+		bm.replayUpdates(ctx, b)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replayUpdates processes updates whose claims are expired.
+func (bm *BotManager) replayUpdates(ctx context.Context, b *bot.Bot) {
+	list, err := bm.ur.UnfinishedUpdates(ctx, time.Now().Add(-claimTimeout))
+	if err != nil {
+		bm.Errorf("Ошибка получения необработанных обновлений: %v", err)
+		return
+	}
+
+	for _, pu := range list {
+		update := &models.Update{}
+		if err = json.Unmarshal(pu.Payload, update); err != nil {
+			bm.Errorf("Ошибка чтения обновления %d: %v", pu.ID, err)
+			continue
+		}
+
+		bm.Printf("Повторная обработка обновления %d", pu.ID)
+		b.ProcessUpdate(ctx, update)
+	}
+}
+
+// cleanupUpdates removes processed updates which could not be delivered again.
+func (bm *BotManager) cleanupUpdates(ctx context.Context) {
+	if _, err := bm.ur.DeleteProcessedUpdates(ctx, time.Now().Add(-processedUpdatesTTL)); err != nil {
+		bm.Errorf("Ошибка удаления обработанных обновлений: %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package botsrv
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"botsrv/pkg/db"
+)
+
+var (
+	reName  = regexp.MustCompile(`^[\p{L}\s\-]+$`)
+	reClass = regexp.MustCompile(`^(\d{1,2})\s*(\p{L})$`)
+)
+
+// FormRules are limits of answers of the application forms.
+type FormRules struct {
+	// MinYear is the earliest graduation year, 1990 by default.
+	MinYear int
+	// MaxYear is the latest graduation year, the current year by default.
+	MaxYear int
+	// ClassNumbers are allowed numbers of the student class, 5-11 by default.
+	ClassNumbers []int
+	// ClassLetters are allowed class letters, e.g. "АБВГ", any Cyrillic letter by default.
+	ClassLetters string
+	// MaxNameLength is a maximum length of the name, 100 by default.
+	MaxNameLength int
+	// MaxLength is a maximum length of other answers, 1000 by default.
+	MaxLength int
+}
+
+func (fr FormRules) minYear() int {
+	if fr.MinYear > 0 {
+		return fr.MinYear
+	}
+	return 1990
+}
+
+func (fr FormRules) maxYear() int {
+	if fr.MaxYear > 0 {
+		return fr.MaxYear
+	}
+	return time.Now().Year()
+}
+
+func (fr FormRules) classNumbers() []int {
+	if len(fr.ClassNumbers) > 0 {
+		return fr.ClassNumbers
+	}
+	return []int{5, 6, 7, 8, 9, 10, 11}
+}
+
+func (fr FormRules) maxNameLength() int {
+	if fr.MaxNameLength > 0 {
+		return fr.MaxNameLength
+	}
+	return 100
+}
+
+func (fr FormRules) maxLength() int {
+	if fr.MaxLength > 0 {
+		return fr.MaxLength
+	}
+	return 1000
+}
+
+// isClassLetter checks that the letter is allowed class letter.
+func (fr FormRules) isClassLetter(letter rune) bool {
+	if fr.ClassLetters == "" {
+		return unicode.Is(unicode.Cyrillic, letter)
+	}
+	return strings.ContainsRune(strings.ToUpper(fr.ClassLetters), unicode.ToUpper(letter))
+}
+
+// FieldError is an error of the form field: error code in the style of db validators and explanation for the applicant.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationError lists errors of the form fields.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Message)
+	}
+	return strings.Join(messages, " ")
+}
+
+// fieldError returns error of the answer with the code.
+func fieldError(code, format string, args ...interface{}) error {
+	return FieldError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// validators check answers of the questions by Validator name of the question config.
+var validators = map[string]func(FormRules, string) error{
+	"name":          FormRules.validateName,
+	"student_class": FormRules.validateStudentClass,
+	"year":          FormRules.validateYear,
+	"class_letter":  FormRules.validateClassLetter,
+	"text":          FormRules.validateText,
+}
+
+func (fr FormRules) validateName(s string) error {
+	if n := utf8.RuneCountInString(s); n > fr.maxNameLength() {
+		return fieldError(db.ErrMaxLength, "Имя должно быть длиной от 2 до %d символов.", fr.maxNameLength())
+	} else if n < 2 {
+		return fieldError(db.ErrWrongValue, "Имя должно быть длиной от 2 до %d символов.", fr.maxNameLength())
+	} else if !reName.MatchString(s) {
+		return fieldError(db.ErrWrongValue, "Имя может содержать только буквы, пробелы и дефисы.")
+	}
+	return nil
+}
+
+func (fr FormRules) validateStudentClass(s string) error {
+	numbers := fr.classNumbers()
+	minNumber, maxNumber := numbers[0], numbers[0]
+	for _, n := range numbers {
+		if n < minNumber {
+			minNumber = n
+		} else if n > maxNumber {
+			maxNumber = n
+		}
+	}
+	err := fieldError(db.ErrWrongValue, "Укажи номер класса от %d до %d и букву, например, 10Б.", minNumber, maxNumber)
+
+	m := reClass.FindStringSubmatch(s)
+	if m == nil {
+		return err
+	}
+
+	number, _ := strconv.Atoi(m[1])
+	letter, _ := utf8.DecodeRuneInString(m[2])
+	if !containsInt(numbers, number) || !fr.isClassLetter(letter) {
+		return err
+	}
+
+	return nil
+}
+
+func (fr FormRules) validateYear(s string) error {
+	year, err := strconv.Atoi(s)
+	if err != nil || year < fr.minYear() || year > fr.maxYear() {
+		return fieldError(db.ErrWrongValue, "Укажи год выпуска четырьмя цифрами от %d до %d, например, 2019.", fr.minYear(), fr.maxYear())
+	}
+	return nil
+}
+
+func (fr FormRules) validateClassLetter(s string) error {
+	letter, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || !fr.isClassLetter(letter) {
+		return fieldError(db.ErrWrongValue, "Укажи одну букву класса, например, А.")
+	}
+	return nil
+}
+
+func (fr FormRules) validateText(s string) error {
+	if utf8.RuneCountInString(s) > fr.maxLength() {
+		return fieldError(db.ErrMaxLength, "Ответ слишком длинный, сократи его до %d символов.", fr.maxLength())
+	}
+	return nil
+}
+
+// fieldErrors checks answers of the form with questions of the role, answers to other fields are checked by length only.
+func (r *role) fieldErrors(f Form) ValidationError {
+	var res ValidationError
+	asked := map[string]bool{}
+	for _, q := range r.questions {
+		asked[q.Field] = true
+
+		answer := strings.TrimSpace(f[q.Field])
+		err := q.Validate(answer)
+		if answer == "" {
+			if q.Optional {
+				continue
+			}
+			err = fieldError(db.ErrEmptyValue, "Не заполнен ответ на вопрос «%s»", q.Text)
+		}
+
+		if fe, ok := err.(FieldError); ok {
+			fe.Field = q.Field
+			res = append(res, fe)
+		}
+	}
+
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		if !asked[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if fe, ok := r.rules.validateText(f[field]).(FieldError); ok {
+			fe.Field = field
+			res = append(res, fe)
+		}
+	}
+
+	return res
+}
+
+// invalidFormText returns message asking the applicant to fix answers of the form.
+func invalidFormText(errs ValidationError) string {
+	text := "Анкета не отправлена на модерацию, исправь, пожалуйста, ответы:\n"
+	for _, fe := range errs {
+		text += "\n• " + fe.Message
+	}
+
+	return text + "\n\nНапиши " + startCommand + ", чтобы заполнить анкету заново."
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
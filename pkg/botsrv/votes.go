@@ -0,0 +1,125 @@
+package botsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"botsrv/pkg/db"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// voteApplication records the vote of the moderator under per-application lock.
+// It returns true if the votes reached the required number and the decision should be applied,
+// otherwise the card is refreshed with current votes.
+func (bm *BotManager) voteApplication(ctx context.Context, b *bot.Bot, app *db.Application, actor *models.User, vote string, comment *string) (bool, error) {
+	var decided bool
+	err := bm.dbo.RunInLock(ctx, applicationLock(app.ID), func(tx *pg.Tx) error {
+		ar := bm.ar.WithTransaction(tx)
+		cur, err := ar.ApplicationByID(ctx, app.ID)
+		if err != nil {
+			return err
+		} else if cur == nil || cur.Status != db.ApplicationStatusPending {
+			return errAlreadyProcessed
+		}
+
+		_, err = ar.VoteApplication(ctx, &db.ApplicationVote{
+			ApplicationID: app.ID,
+			VoterTgID:     actor.ID,
+			VoterName:     userName(actor),
+			Vote:          vote,
+			Comment:       comment,
+		})
+		if err != nil {
+			return err
+		}
+
+		votes, err := ar.ApplicationVotes(ctx, app.ID)
+		if err != nil {
+			return err
+		}
+
+		app.AdminMessageID = cur.AdminMessageID
+		if decided = len(votesBy(votes, vote)) >= bm.requiredVotes(app, vote); decided || app.AdminMessageID == nil {
+			return nil
+		}
+
+		return bm.enqueue(ctx, bm.or.WithTransaction(tx), editMessageText(&bot.EditMessageTextParams{
+			Text:        bm.renderCard(app, votes),
+			ChatID:      bm.cardChat(app),
+			MessageID:   *app.AdminMessageID,
+			ReplyMarkup: bm.moderationKeyboard(app.ID),
+		}).forApplication(app.ID))
+	})
+	if errors.Is(err, errAlreadyProcessed) {
+		return false, err
+	} else if err != nil {
+		bm.Errorf("Ошибка сохранения голоса за заявку %d: %v", app.ID, err)
+		return false, err
+	}
+
+	bm.wakeOutbox()
+	return decided, nil
+}
+
+// requiredVotes returns number of votes of the kind required to make the decision on the application.
+func (bm *BotManager) requiredVotes(app *db.Application, vote string) int {
+	if vote == db.ApplicationVoteReject {
+		return bm.cfg.rejectVotes()
+	}
+	return bm.approvals(app.Role)
+}
+
+// renderCard returns application card with votes of moderators.
+func (bm *BotManager) renderCard(app *db.Application, votes []db.ApplicationVote) string {
+	card, err := bm.applicationCard(app)
+	if err != nil {
+		bm.Errorf("Ошибка обработки заявки %d: %v", app.ID, err)
+	}
+
+	if len(votes) == 0 {
+		return card
+	}
+
+	return card + "\n\n" + strings.Join([]string{
+		bm.votesLine("За", app, votes, db.ApplicationVoteApprove),
+		bm.votesLine("Против", app, votes, db.ApplicationVoteReject),
+	}, "\n")
+}
+
+// votesLine returns count of the votes of the kind and names of the voters, e.g. "За (1/2): Иван Иванов".
+func (bm *BotManager) votesLine(title string, app *db.Application, votes []db.ApplicationVote, vote string) string {
+	names := votesBy(votes, vote)
+	res := fmt.Sprintf("%s (%d/%d)", title, len(names), bm.requiredVotes(app, vote))
+	if len(names) > 0 {
+		res += ": " + strings.Join(names, ", ")
+	}
+
+	return res
+}
+
+// votesBy returns names of moderators who voted the same way.
+func votesBy(votes []db.ApplicationVote, vote string) []string {
+	var names []string
+	for _, v := range votes {
+		if v.Vote == vote {
+			names = append(names, v.VoterName)
+		}
+	}
+
+	return names
+}
+
+// applicationVotes returns votes for the application, errors are logged.
+func (bm *BotManager) applicationVotes(ctx context.Context, app *db.Application) []db.ApplicationVote {
+	votes, err := bm.ar.ApplicationVotes(ctx, app.ID)
+	if err != nil {
+		bm.Errorf("Ошибка получения голосов за заявку %d: %v", app.ID, err)
+	}
+
+	return votes
+}
@@ -0,0 +1,100 @@
+package botsrv
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// FormModeWebApp fills the form in Telegram Mini App served by the bot server.
+const FormModeWebApp = "web_app"
+
+// initDataTTL is a maximum age of the Mini App init data.
+const initDataTTL = 24 * time.Hour
+
+var ErrInvalidInitData = errors.New("invalid init data")
+
+// webAppKeyboard returns button which opens the Mini App with the form of the role.
+func (bm *BotManager) webAppKeyboard(role string) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{{{
+			Text:   "Заполнить анкету",
+			WebApp: &models.WebAppInfo{URL: bm.cfg.WebAppURL + "?role=" + url.QueryEscape(role)},
+		}}},
+	}
+}
+
+// VerifyInitData checks signature and age of the Mini App init data and returns the Telegram user.
+func (bm *BotManager) VerifyInitData(initData string) (*bot.WebAppUser, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, ErrInvalidInitData
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil || time.Since(time.Unix(authDate, 0)) > initDataTTL {
+		return nil, ErrInvalidInitData
+	}
+
+	user, ok := bot.ValidateWebappRequest(values, bm.cfg.Token)
+	if !ok || user.ID == 0 {
+		return nil, ErrInvalidInitData
+	}
+
+	return user, nil
+}
+
+// SubmitWebAppForm validates the form filled in the Mini App and sends it to moderation.
+// Telegram ID and username are taken from the verified init data, ValidationError is returned for invalid answers.
+func (bm *BotManager) SubmitWebAppForm(ctx context.Context, b *bot.Bot, initData, role string, answers map[string]string) error {
+	user, err := bm.VerifyInitData(initData)
+	if err != nil {
+		return err
+	}
+
+	r := bm.role(role)
+	if r == nil || len(r.questions) == 0 {
+		return ErrUnknownRole
+	}
+
+	form := Form{}
+	for _, q := range r.questions {
+		if answer := strings.TrimSpace(answers[q.Field]); answer != "" {
+			form[q.Field] = answer
+		}
+	}
+
+	if errs := r.fieldErrors(form); len(errs) > 0 {
+		return errs
+	}
+
+	form["tgId"] = strconv.FormatInt(user.ID, 10)
+	form["nickname"] = user.Username
+
+	return bm.submitApplication(ctx, b, role, form["tgId"], form)
+}
+
+// WebAppQuestion is a question of the Mini App form.
+type WebAppQuestion struct {
+	Field    string `json:"field"`
+	Text     string `json:"text"`
+	Optional bool   `json:"optional"`
+}
+
+// WebAppQuestions returns questions of the Mini App forms by role.
+func (bm *BotManager) WebAppQuestions() map[string][]WebAppQuestion {
+	res := make(map[string][]WebAppQuestion, len(bm.roles))
+	for _, r := range bm.roles {
+		for _, q := range r.questions {
+			res[r.Name] = append(res[r.Name], WebAppQuestion{Field: q.Field, Text: q.Text, Optional: q.Optional})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,512 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+type AdmissionRepo struct {
+	db      orm.DB
+	filters map[string][]Filter
+	sort    map[string][]SortField
+	join    map[string][]string
+}
+
+// NewAdmissionRepo returns new repository
+func NewAdmissionRepo(db orm.DB) AdmissionRepo {
+	return AdmissionRepo{
+		db: db,
+		filters: map[string][]Filter{
+			Tables.Application.Name:       {},
+			Tables.ModerationLog.Name:     {},
+			Tables.ApplicationVote.Name:   {},
+			Tables.InviteLink.Name:        {},
+			Tables.Conversation.Name:      {},
+			Tables.RegistrationToken.Name: {},
+		},
+		sort: map[string][]SortField{
+			Tables.Application.Name:       {{Column: Columns.Application.CreatedAt, Direction: SortDesc}},
+			Tables.ModerationLog.Name:     {{Column: Columns.ModerationLog.CreatedAt, Direction: SortDesc}},
+			Tables.ApplicationVote.Name:   {{Column: Columns.ApplicationVote.CreatedAt, Direction: SortAsc}},
+			Tables.InviteLink.Name:        {{Column: Columns.InviteLink.CreatedAt, Direction: SortDesc}},
+			Tables.Conversation.Name:      {{Column: Columns.Conversation.CreatedAt, Direction: SortDesc}},
+			Tables.RegistrationToken.Name: {{Column: Columns.RegistrationToken.CreatedAt, Direction: SortDesc}},
+		},
+		join: map[string][]string{
+			Tables.Application.Name:       {TableColumns},
+			Tables.ModerationLog.Name:     {TableColumns, Columns.ModerationLog.Application},
+			Tables.ApplicationVote.Name:   {TableColumns, Columns.ApplicationVote.Application},
+			Tables.InviteLink.Name:        {TableColumns, Columns.InviteLink.Application},
+			Tables.Conversation.Name:      {TableColumns},
+			Tables.RegistrationToken.Name: {TableColumns},
+		},
+	}
+}
+
+// WithTransaction is a function that wraps AdmissionRepo with pg.Tx transaction.
+func (ar AdmissionRepo) WithTransaction(tx *pg.Tx) AdmissionRepo {
+	ar.db = tx
+	return ar
+}
+
+/*** Application ***/
+
+// FullApplication returns full joins with all columns
+func (ar AdmissionRepo) FullApplication() OpFunc {
+	return WithColumns(ar.join[Tables.Application.Name]...)
+}
+
+// DefaultApplicationSort returns default sort.
+func (ar AdmissionRepo) DefaultApplicationSort() OpFunc {
+	return WithSort(ar.sort[Tables.Application.Name]...)
+}
+
+// ApplicationByID is a function that returns Application by ID(s) or nil.
+func (ar AdmissionRepo) ApplicationByID(ctx context.Context, id int, ops ...OpFunc) (*Application, error) {
+	return ar.OneApplication(ctx, &ApplicationSearch{ID: &id}, ops...)
+}
+
+// OneApplication is a function that returns one Application by filters. It could return pg.ErrMultiRows.
+func (ar AdmissionRepo) OneApplication(ctx context.Context, search *ApplicationSearch, ops ...OpFunc) (*Application, error) {
+	obj := &Application{}
+	err := buildQuery(ctx, ar.db, obj, search, ar.filters[Tables.Application.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// ApplicationsByFilters returns Application list.
+func (ar AdmissionRepo) ApplicationsByFilters(ctx context.Context, search *ApplicationSearch, pager Pager, ops ...OpFunc) (applications []Application, err error) {
+	err = buildQuery(ctx, ar.db, &applications, search, ar.filters[Tables.Application.Name], pager, ops...).Select()
+	return
+}
+
+// CountApplications returns count
+func (ar AdmissionRepo) CountApplications(ctx context.Context, search *ApplicationSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ar.db, &Application{}, search, ar.filters[Tables.Application.Name], PagerOne, ops...).Count()
+}
+
+// AddApplication adds Application to DB.
+func (ar AdmissionRepo) AddApplication(ctx context.Context, application *Application, ops ...OpFunc) (*Application, error) {
+	q := ar.db.ModelContext(ctx, application)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Application.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return application, err
+}
+
+// UpdateApplication updates Application in DB.
+func (ar AdmissionRepo) UpdateApplication(ctx context.Context, application *Application, ops ...OpFunc) (bool, error) {
+	q := ar.db.ModelContext(ctx, application).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Application.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteApplication deletes Application from DB.
+func (ar AdmissionRepo) DeleteApplication(ctx context.Context, id int) (deleted bool, err error) {
+	application := &Application{ID: id}
+
+	res, err := ar.db.ModelContext(ctx, application).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+/*** ModerationLog ***/
+
+// FullModerationLog returns full joins with all columns
+func (ar AdmissionRepo) FullModerationLog() OpFunc {
+	return WithColumns(ar.join[Tables.ModerationLog.Name]...)
+}
+
+// DefaultModerationLogSort returns default sort.
+func (ar AdmissionRepo) DefaultModerationLogSort() OpFunc {
+	return WithSort(ar.sort[Tables.ModerationLog.Name]...)
+}
+
+// ModerationLogByID is a function that returns ModerationLog by ID(s) or nil.
+func (ar AdmissionRepo) ModerationLogByID(ctx context.Context, id int, ops ...OpFunc) (*ModerationLog, error) {
+	return ar.OneModerationLog(ctx, &ModerationLogSearch{ID: &id}, ops...)
+}
+
+// OneModerationLog is a function that returns one ModerationLog by filters. It could return pg.ErrMultiRows.
+func (ar AdmissionRepo) OneModerationLog(ctx context.Context, search *ModerationLogSearch, ops ...OpFunc) (*ModerationLog, error) {
+	obj := &ModerationLog{}
+	err := buildQuery(ctx, ar.db, obj, search, ar.filters[Tables.ModerationLog.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// ModerationLogsByFilters returns ModerationLog list.
+func (ar AdmissionRepo) ModerationLogsByFilters(ctx context.Context, search *ModerationLogSearch, pager Pager, ops ...OpFunc) (moderationLogs []ModerationLog, err error) {
+	err = buildQuery(ctx, ar.db, &moderationLogs, search, ar.filters[Tables.ModerationLog.Name], pager, ops...).Select()
+	return
+}
+
+// CountModerationLogs returns count
+func (ar AdmissionRepo) CountModerationLogs(ctx context.Context, search *ModerationLogSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ar.db, &ModerationLog{}, search, ar.filters[Tables.ModerationLog.Name], PagerOne, ops...).Count()
+}
+
+// AddModerationLog adds ModerationLog to DB.
+func (ar AdmissionRepo) AddModerationLog(ctx context.Context, moderationLog *ModerationLog, ops ...OpFunc) (*ModerationLog, error) {
+	q := ar.db.ModelContext(ctx, moderationLog)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.ModerationLog.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return moderationLog, err
+}
+
+/*** ApplicationVote ***/
+
+// FullApplicationVote returns full joins with all columns
+func (ar AdmissionRepo) FullApplicationVote() OpFunc {
+	return WithColumns(ar.join[Tables.ApplicationVote.Name]...)
+}
+
+// DefaultApplicationVoteSort returns default sort.
+func (ar AdmissionRepo) DefaultApplicationVoteSort() OpFunc {
+	return WithSort(ar.sort[Tables.ApplicationVote.Name]...)
+}
+
+// ApplicationVoteByID is a function that returns ApplicationVote by ID(s) or nil.
+func (ar AdmissionRepo) ApplicationVoteByID(ctx context.Context, id int, ops ...OpFunc) (*ApplicationVote, error) {
+	return ar.OneApplicationVote(ctx, &ApplicationVoteSearch{ID: &id}, ops...)
+}
+
+// OneApplicationVote is a function that returns one ApplicationVote by filters. It could return pg.ErrMultiRows.
+func (ar AdmissionRepo) OneApplicationVote(ctx context.Context, search *ApplicationVoteSearch, ops ...OpFunc) (*ApplicationVote, error) {
+	obj := &ApplicationVote{}
+	err := buildQuery(ctx, ar.db, obj, search, ar.filters[Tables.ApplicationVote.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// ApplicationVotesByFilters returns ApplicationVote list.
+func (ar AdmissionRepo) ApplicationVotesByFilters(ctx context.Context, search *ApplicationVoteSearch, pager Pager, ops ...OpFunc) (applicationVotes []ApplicationVote, err error) {
+	err = buildQuery(ctx, ar.db, &applicationVotes, search, ar.filters[Tables.ApplicationVote.Name], pager, ops...).Select()
+	return
+}
+
+// CountApplicationVotes returns count
+func (ar AdmissionRepo) CountApplicationVotes(ctx context.Context, search *ApplicationVoteSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ar.db, &ApplicationVote{}, search, ar.filters[Tables.ApplicationVote.Name], PagerOne, ops...).Count()
+}
+
+// AddApplicationVote adds ApplicationVote to DB.
+func (ar AdmissionRepo) AddApplicationVote(ctx context.Context, applicationVote *ApplicationVote, ops ...OpFunc) (*ApplicationVote, error) {
+	q := ar.db.ModelContext(ctx, applicationVote)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.ApplicationVote.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return applicationVote, err
+}
+
+// UpdateApplicationVote updates ApplicationVote in DB.
+func (ar AdmissionRepo) UpdateApplicationVote(ctx context.Context, applicationVote *ApplicationVote, ops ...OpFunc) (bool, error) {
+	q := ar.db.ModelContext(ctx, applicationVote).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.ApplicationVote.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteApplicationVote deletes ApplicationVote from DB.
+func (ar AdmissionRepo) DeleteApplicationVote(ctx context.Context, id int) (deleted bool, err error) {
+	applicationVote := &ApplicationVote{ID: id}
+
+	res, err := ar.db.ModelContext(ctx, applicationVote).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+/*** InviteLink ***/
+
+// FullInviteLink returns full joins with all columns
+func (ar AdmissionRepo) FullInviteLink() OpFunc {
+	return WithColumns(ar.join[Tables.InviteLink.Name]...)
+}
+
+// DefaultInviteLinkSort returns default sort.
+func (ar AdmissionRepo) DefaultInviteLinkSort() OpFunc {
+	return WithSort(ar.sort[Tables.InviteLink.Name]...)
+}
+
+// InviteLinkByID is a function that returns InviteLink by ID(s) or nil.
+func (ar AdmissionRepo) InviteLinkByID(ctx context.Context, id int, ops ...OpFunc) (*InviteLink, error) {
+	return ar.OneInviteLink(ctx, &InviteLinkSearch{ID: &id}, ops...)
+}
+
+// OneInviteLink is a function that returns one InviteLink by filters. It could return pg.ErrMultiRows.
+func (ar AdmissionRepo) OneInviteLink(ctx context.Context, search *InviteLinkSearch, ops ...OpFunc) (*InviteLink, error) {
+	obj := &InviteLink{}
+	err := buildQuery(ctx, ar.db, obj, search, ar.filters[Tables.InviteLink.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// InviteLinksByFilters returns InviteLink list.
+func (ar AdmissionRepo) InviteLinksByFilters(ctx context.Context, search *InviteLinkSearch, pager Pager, ops ...OpFunc) (inviteLinks []InviteLink, err error) {
+	err = buildQuery(ctx, ar.db, &inviteLinks, search, ar.filters[Tables.InviteLink.Name], pager, ops...).Select()
+	return
+}
+
+// CountInviteLinks returns count
+func (ar AdmissionRepo) CountInviteLinks(ctx context.Context, search *InviteLinkSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ar.db, &InviteLink{}, search, ar.filters[Tables.InviteLink.Name], PagerOne, ops...).Count()
+}
+
+// AddInviteLink adds InviteLink to DB.
+func (ar AdmissionRepo) AddInviteLink(ctx context.Context, inviteLink *InviteLink, ops ...OpFunc) (*InviteLink, error) {
+	q := ar.db.ModelContext(ctx, inviteLink)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.InviteLink.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return inviteLink, err
+}
+
+// UpdateInviteLink updates InviteLink in DB.
+func (ar AdmissionRepo) UpdateInviteLink(ctx context.Context, inviteLink *InviteLink, ops ...OpFunc) (bool, error) {
+	q := ar.db.ModelContext(ctx, inviteLink).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.InviteLink.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteInviteLink deletes InviteLink from DB.
+func (ar AdmissionRepo) DeleteInviteLink(ctx context.Context, id int) (deleted bool, err error) {
+	inviteLink := &InviteLink{ID: id}
+
+	res, err := ar.db.ModelContext(ctx, inviteLink).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+/*** Conversation ***/
+
+// FullConversation returns full joins with all columns
+func (ar AdmissionRepo) FullConversation() OpFunc {
+	return WithColumns(ar.join[Tables.Conversation.Name]...)
+}
+
+// DefaultConversationSort returns default sort.
+func (ar AdmissionRepo) DefaultConversationSort() OpFunc {
+	return WithSort(ar.sort[Tables.Conversation.Name]...)
+}
+
+// ConversationByID is a function that returns Conversation by ID(s) or nil.
+func (ar AdmissionRepo) ConversationByID(ctx context.Context, id int, ops ...OpFunc) (*Conversation, error) {
+	return ar.OneConversation(ctx, &ConversationSearch{ID: &id}, ops...)
+}
+
+// OneConversation is a function that returns one Conversation by filters. It could return pg.ErrMultiRows.
+func (ar AdmissionRepo) OneConversation(ctx context.Context, search *ConversationSearch, ops ...OpFunc) (*Conversation, error) {
+	obj := &Conversation{}
+	err := buildQuery(ctx, ar.db, obj, search, ar.filters[Tables.Conversation.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// ConversationsByFilters returns Conversation list.
+func (ar AdmissionRepo) ConversationsByFilters(ctx context.Context, search *ConversationSearch, pager Pager, ops ...OpFunc) (conversations []Conversation, err error) {
+	err = buildQuery(ctx, ar.db, &conversations, search, ar.filters[Tables.Conversation.Name], pager, ops...).Select()
+	return
+}
+
+// CountConversations returns count
+func (ar AdmissionRepo) CountConversations(ctx context.Context, search *ConversationSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ar.db, &Conversation{}, search, ar.filters[Tables.Conversation.Name], PagerOne, ops...).Count()
+}
+
+// AddConversation adds Conversation to DB.
+func (ar AdmissionRepo) AddConversation(ctx context.Context, conversation *Conversation, ops ...OpFunc) (*Conversation, error) {
+	q := ar.db.ModelContext(ctx, conversation)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Conversation.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return conversation, err
+}
+
+// UpdateConversation updates Conversation in DB.
+func (ar AdmissionRepo) UpdateConversation(ctx context.Context, conversation *Conversation, ops ...OpFunc) (bool, error) {
+	q := ar.db.ModelContext(ctx, conversation).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Conversation.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteConversation deletes Conversation from DB.
+func (ar AdmissionRepo) DeleteConversation(ctx context.Context, id int) (deleted bool, err error) {
+	conversation := &Conversation{ID: id}
+
+	res, err := ar.db.ModelContext(ctx, conversation).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+/*** RegistrationToken ***/
+
+// FullRegistrationToken returns full joins with all columns
+func (ar AdmissionRepo) FullRegistrationToken() OpFunc {
+	return WithColumns(ar.join[Tables.RegistrationToken.Name]...)
+}
+
+// DefaultRegistrationTokenSort returns default sort.
+func (ar AdmissionRepo) DefaultRegistrationTokenSort() OpFunc {
+	return WithSort(ar.sort[Tables.RegistrationToken.Name]...)
+}
+
+// RegistrationTokenByID is a function that returns RegistrationToken by ID(s) or nil.
+func (ar AdmissionRepo) RegistrationTokenByID(ctx context.Context, id int, ops ...OpFunc) (*RegistrationToken, error) {
+	return ar.OneRegistrationToken(ctx, &RegistrationTokenSearch{ID: &id}, ops...)
+}
+
+// OneRegistrationToken is a function that returns one RegistrationToken by filters. It could return pg.ErrMultiRows.
+func (ar AdmissionRepo) OneRegistrationToken(ctx context.Context, search *RegistrationTokenSearch, ops ...OpFunc) (*RegistrationToken, error) {
+	obj := &RegistrationToken{}
+	err := buildQuery(ctx, ar.db, obj, search, ar.filters[Tables.RegistrationToken.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// RegistrationTokensByFilters returns RegistrationToken list.
+func (ar AdmissionRepo) RegistrationTokensByFilters(ctx context.Context, search *RegistrationTokenSearch, pager Pager, ops ...OpFunc) (registrationTokens []RegistrationToken, err error) {
+	err = buildQuery(ctx, ar.db, &registrationTokens, search, ar.filters[Tables.RegistrationToken.Name], pager, ops...).Select()
+	return
+}
+
+// CountRegistrationTokens returns count
+func (ar AdmissionRepo) CountRegistrationTokens(ctx context.Context, search *RegistrationTokenSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ar.db, &RegistrationToken{}, search, ar.filters[Tables.RegistrationToken.Name], PagerOne, ops...).Count()
+}
+
+// AddRegistrationToken adds RegistrationToken to DB.
+func (ar AdmissionRepo) AddRegistrationToken(ctx context.Context, registrationToken *RegistrationToken, ops ...OpFunc) (*RegistrationToken, error) {
+	q := ar.db.ModelContext(ctx, registrationToken)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.RegistrationToken.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return registrationToken, err
+}
+
+// UpdateRegistrationToken updates RegistrationToken in DB.
+func (ar AdmissionRepo) UpdateRegistrationToken(ctx context.Context, registrationToken *RegistrationToken, ops ...OpFunc) (bool, error) {
+	q := ar.db.ModelContext(ctx, registrationToken).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.RegistrationToken.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteRegistrationToken deletes RegistrationToken from DB.
+func (ar AdmissionRepo) DeleteRegistrationToken(ctx context.Context, id int) (deleted bool, err error) {
+	registrationToken := &RegistrationToken{ID: id}
+
+	res, err := ar.db.ModelContext(ctx, registrationToken).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+const (
+	// application statuses
+	ApplicationStatusPending  = "pending"
+	ApplicationStatusAccepted = "accepted"
+	ApplicationStatusRejected = "rejected"
+	ApplicationStatusJoined   = "joined"
+	ApplicationStatusExpired  = "expired"
+	ApplicationStatusBanned   = "banned"
+
+	// moderation log actions
+	ModerationActionAccept = "accept"
+	ModerationActionReject = "reject"
+	ModerationActionBan    = "ban"
+	ModerationActionUndo   = "undo"
+	ModerationActionKick   = "kick"
+
+	// application votes
+	ApplicationVoteApprove = "approve"
+	ApplicationVoteReject  = "reject"
+)
+
+// SetApplicationStatus updates status of the application, decision time is set for accepted, rejected and banned statuses.
+// Reject reason of the application is saved along with the status.
+func (ar AdmissionRepo) SetApplicationStatus(ctx context.Context, application *Application, status string) (bool, error) {
+	now := time.Now()
+	application.Status = status
+	application.UpdatedAt = now
+	if status == ApplicationStatusAccepted || status == ApplicationStatusRejected || status == ApplicationStatusBanned {
+		application.DecidedAt = &now
+	}
+
+	return ar.UpdateApplication(ctx, application, WithColumns(Columns.Application.Status, Columns.Application.UpdatedAt, Columns.Application.DecidedAt, Columns.Application.RejectReason))
+}
+
+// SetApplicationAdminMessage stores ID of the moderation card sent to the admin chat.
+func (ar AdmissionRepo) SetApplicationAdminMessage(ctx context.Context, application *Application, messageID int) (bool, error) {
+	application.AdminMessageID = &messageID
+	return ar.UpdateApplication(ctx, application, WithColumns(Columns.Application.AdminMessageID))
+}
+
+// SetApplicationNotified marks the application as notified, the decision could not be undone after that.
+func (ar AdmissionRepo) SetApplicationNotified(ctx context.Context, application *Application) (bool, error) {
+	now := time.Now()
+	application.NotifiedAt = &now
+	return ar.UpdateApplication(ctx, application, WithColumns(Columns.Application.NotifiedAt))
+}
+
+// UnnotifiedApplications returns accepted and rejected applications decided before the given time whose applicants were not notified yet.
+func (ar AdmissionRepo) UnnotifiedApplications(ctx context.Context, decidedBefore time.Time) ([]Application, error) {
+	notNotified := true
+	return ar.ApplicationsByFilters(ctx, &ApplicationSearch{
+		Statuses:       []string{ApplicationStatusAccepted, ApplicationStatusRejected},
+		DecidedAtTo:    &decidedBefore,
+		NotifiedAtNull: &notNotified,
+	}, PagerNoLimit)
+}
+
+// PendingApplications returns all pending applications of the Telegram user.
+func (ar AdmissionRepo) PendingApplications(ctx context.Context, tgID int64) ([]Application, error) {
+	status := ApplicationStatusPending
+	return ar.ApplicationsByFilters(ctx, &ApplicationSearch{TgID: &tgID, Status: &status}, PagerNoLimit)
+}
+
+// IsBanned checks if the Telegram user was banned by moderators.
+func (ar AdmissionRepo) IsBanned(ctx context.Context, tgID int64) (bool, error) {
+	status := ApplicationStatusBanned
+	count, err := ar.CountApplications(ctx, &ApplicationSearch{TgID: &tgID, Status: &status})
+	return count > 0, err
+}
+
+// ApplicationVotes returns votes of moderators for the application in order of voting.
+func (ar AdmissionRepo) ApplicationVotes(ctx context.Context, applicationID int) ([]ApplicationVote, error) {
+	return ar.ApplicationVotesByFilters(ctx, &ApplicationVoteSearch{ApplicationID: &applicationID}, PagerNoLimit, ar.DefaultApplicationVoteSort())
+}
+
+// VoteApplication adds vote of the moderator for the application or replaces the previous one.
+func (ar AdmissionRepo) VoteApplication(ctx context.Context, vote *ApplicationVote) (*ApplicationVote, error) {
+	prev, err := ar.OneApplicationVote(ctx, &ApplicationVoteSearch{ApplicationID: &vote.ApplicationID, VoterTgID: &vote.VoterTgID})
+	if err != nil {
+		return nil, err
+	} else if prev == nil {
+		return ar.AddApplicationVote(ctx, vote)
+	}
+
+	vote.ID = prev.ID
+	_, err = ar.UpdateApplicationVote(ctx, vote, WithColumns(Columns.ApplicationVote.VoterName, Columns.ApplicationVote.Vote, Columns.ApplicationVote.Comment))
+	return vote, err
+}
+
+// LastApplication returns the latest application of the Telegram user or nil.
+func (ar AdmissionRepo) LastApplication(ctx context.Context, tgID int64) (*Application, error) {
+	list, err := ar.ApplicationsByFilters(ctx, &ApplicationSearch{TgID: &tgID}, Pager{Page: 1, PageSize: 1}, ar.DefaultApplicationSort())
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// LastAcceptedApplication returns the latest accepted application of the Telegram user or nil.
+func (ar AdmissionRepo) LastAcceptedApplication(ctx context.Context, tgID int64) (*Application, error) {
+	status := ApplicationStatusAccepted
+	list, err := ar.ApplicationsByFilters(ctx, &ApplicationSearch{TgID: &tgID, Status: &status}, Pager{Page: 1, PageSize: 1}, ar.DefaultApplicationSort())
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// LastAdmittedApplication returns the latest accepted or joined application of the Telegram user or nil.
+func (ar AdmissionRepo) LastAdmittedApplication(ctx context.Context, tgID int64) (*Application, error) {
+	list, err := ar.ApplicationsByFilters(ctx, &ApplicationSearch{
+		TgID:     &tgID,
+		Statuses: []string{ApplicationStatusAccepted, ApplicationStatusJoined},
+	}, Pager{Page: 1, PageSize: 1}, ar.DefaultApplicationSort())
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// InviteLinkByLink returns invite link by its URL or nil.
+func (ar AdmissionRepo) InviteLinkByLink(ctx context.Context, link string) (*InviteLink, error) {
+	return ar.OneInviteLink(ctx, &InviteLinkSearch{Link: &link})
+}
+
+// ActiveInviteLink returns not used, not revoked and not expired invite link of the application or nil.
+func (ar AdmissionRepo) ActiveInviteLink(ctx context.Context, applicationID int) (*InviteLink, error) {
+	now, null := time.Now(), true
+	list, err := ar.InviteLinksByFilters(ctx, &InviteLinkSearch{
+		ApplicationID: &applicationID,
+		ExpiresAtFrom: &now,
+		UsedAtNull:    &null,
+		RevokedAtNull: &null,
+	}, Pager{Page: 1, PageSize: 1}, ar.DefaultInviteLinkSort())
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// ExpiredInviteLinks returns expired invite links which were neither used nor revoked.
+func (ar AdmissionRepo) ExpiredInviteLinks(ctx context.Context) ([]InviteLink, error) {
+	now, null := time.Now(), true
+	return ar.InviteLinksByFilters(ctx, &InviteLinkSearch{
+		ExpiresAtTo:   &now,
+		UsedAtNull:    &null,
+		RevokedAtNull: &null,
+	}, PagerNoLimit)
+}
+
+// SetInviteLinkUsed marks the invite link as used.
+func (ar AdmissionRepo) SetInviteLinkUsed(ctx context.Context, link *InviteLink) (bool, error) {
+	now := time.Now()
+	link.UsedAt = &now
+	return ar.UpdateInviteLink(ctx, link, WithColumns(Columns.InviteLink.UsedAt))
+}
+
+// SetInviteLinkRevoked marks the invite link as revoked.
+func (ar AdmissionRepo) SetInviteLinkRevoked(ctx context.Context, link *InviteLink) (bool, error) {
+	now := time.Now()
+	link.RevokedAt = &now
+	return ar.UpdateInviteLink(ctx, link, WithColumns(Columns.InviteLink.RevokedAt))
+}
+
+// ConversationByTgID returns registration dialogue state of the Telegram user or nil.
+func (ar AdmissionRepo) ConversationByTgID(ctx context.Context, tgID int64) (*Conversation, error) {
+	return ar.OneConversation(ctx, &ConversationSearch{TgID: &tgID})
+}
+
+// SaveConversation adds or updates registration dialogue state.
+func (ar AdmissionRepo) SaveConversation(ctx context.Context, conversation *Conversation) (*Conversation, error) {
+	conversation.UpdatedAt = time.Now()
+	if conversation.ID == 0 {
+		return ar.AddConversation(ctx, conversation)
+	}
+
+	_, err := ar.UpdateConversation(ctx, conversation)
+	return conversation, err
+}
+
+// UsableRegistrationToken returns the registration token if it is neither used nor expired, nil otherwise.
+func (ar AdmissionRepo) UsableRegistrationToken(ctx context.Context, token string) (*RegistrationToken, error) {
+	search := &RegistrationTokenSearch{Token: &token}
+	search.With("? is null", pg.Ident(Tables.RegistrationToken.Alias+"."+Columns.RegistrationToken.UsedAt))
+	search.With("? > now()", pg.Ident(Tables.RegistrationToken.Alias+"."+Columns.RegistrationToken.ExpiresAt))
+
+	return ar.OneRegistrationToken(ctx, search)
+}
+
+// RedeemRegistrationToken marks the registration token as used and returns it.
+// Nil is returned for unknown, already used or expired tokens.
+func (ar AdmissionRepo) RedeemRegistrationToken(ctx context.Context, token string) (*RegistrationToken, error) {
+	rt := &RegistrationToken{}
+	res, err := ar.db.ModelContext(ctx, rt).
+		Set("? = now()", pg.Ident(Columns.RegistrationToken.UsedAt)).
+		Where("? = ?", pg.Ident(Columns.RegistrationToken.Token), token).
+		Where("? is null", pg.Ident(Columns.RegistrationToken.UsedAt)).
+		Where("? > now()", pg.Ident(Columns.RegistrationToken.ExpiresAt)).
+		Returning("*").
+		Update()
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else if res.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	return rt, nil
+}
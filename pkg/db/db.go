@@ -51,9 +51,14 @@ func (db *DB) runInTransaction(ctx context.Context, fns ...func(*pg.Tx) error) e
 	})
 }
 
+// lockKey returns key of the advisory lock by its name.
+func (db *DB) lockKey(lockName string) int64 {
+	return int64(crc64.Checksum([]byte(lockName), db.crcTable))
+}
+
 // RunInLock runs chain of functions in transaction with lock until first error
 func (db *DB) RunInLock(ctx context.Context, lockName string, fns ...func(*pg.Tx) error) error {
-	lock := int64(crc64.Checksum([]byte(lockName), db.crcTable))
+	lock := db.lockKey(lockName)
 
 	return db.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
 		if _, err = tx.Exec("select pg_advisory_xact_lock(?) -- ?", lock, lockName); err != nil {
@@ -70,6 +75,40 @@ func (db *DB) RunInLock(ctx context.Context, lockName string, fns ...func(*pg.Tx
 	})
 }
 
+// SessionLock is a session-level advisory lock held on a dedicated connection until it is released or the connection is lost.
+type SessionLock struct {
+	conn *pg.Conn
+	key  int64
+	name string
+}
+
+// TryLock takes session-level advisory lock with the same key as RunInLock, nil is returned if the lock is held by another session.
+func (db *DB) TryLock(ctx context.Context, lockName string) (*SessionLock, error) {
+	l := &SessionLock{conn: db.Conn(), key: db.lockKey(lockName), name: lockName}
+
+	var locked bool
+	if _, err := l.conn.QueryOneContext(ctx, pg.Scan(&locked), "select pg_try_advisory_lock(?) -- ?", l.key, l.name); err != nil || !locked {
+		l.conn.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Check checks that the connection holding the lock is alive, the lock is lost otherwise.
+func (l *SessionLock) Check(ctx context.Context) error {
+	_, err := l.conn.ExecContext(ctx, "select 1 -- ?", l.name)
+	return err
+}
+
+// Release releases the lock and the connection.
+func (l *SessionLock) Release(ctx context.Context) error {
+	defer l.conn.Close()
+
+	_, err := l.conn.ExecContext(ctx, "select pg_advisory_unlock(?) -- ?", l.key, l.name)
+	return err
+}
+
 // buildQuery applies all functions to orm query.
 func buildQuery(ctx context.Context, db orm.DB, model interface{}, search Searcher, filters []Filter, pager Pager, ops ...OpFunc) *orm.Query {
 	q := db.ModelContext(ctx, model)
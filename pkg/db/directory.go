@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+type DirectoryRepo struct {
+	db      orm.DB
+	filters map[string][]Filter
+	sort    map[string][]SortField
+	join    map[string][]string
+}
+
+// NewDirectoryRepo returns new repository
+func NewDirectoryRepo(db orm.DB) DirectoryRepo {
+	return DirectoryRepo{
+		db: db,
+		filters: map[string][]Filter{
+			Tables.Member.Name: {},
+		},
+		sort: map[string][]SortField{
+			Tables.Member.Name: {{Column: Columns.Member.Name, Direction: SortAsc}},
+		},
+		join: map[string][]string{
+			Tables.Member.Name: {TableColumns, Columns.Member.Application},
+		},
+	}
+}
+
+// WithTransaction is a function that wraps DirectoryRepo with pg.Tx transaction.
+func (dr DirectoryRepo) WithTransaction(tx *pg.Tx) DirectoryRepo {
+	dr.db = tx
+	return dr
+}
+
+/*** Member ***/
+
+// FullMember returns full joins with all columns
+func (dr DirectoryRepo) FullMember() OpFunc {
+	return WithColumns(dr.join[Tables.Member.Name]...)
+}
+
+// DefaultMemberSort returns default sort.
+func (dr DirectoryRepo) DefaultMemberSort() OpFunc {
+	return WithSort(dr.sort[Tables.Member.Name]...)
+}
+
+// MemberByID is a function that returns Member by ID(s) or nil.
+func (dr DirectoryRepo) MemberByID(ctx context.Context, id int, ops ...OpFunc) (*Member, error) {
+	return dr.OneMember(ctx, &MemberSearch{ID: &id}, ops...)
+}
+
+// OneMember is a function that returns one Member by filters. It could return pg.ErrMultiRows.
+func (dr DirectoryRepo) OneMember(ctx context.Context, search *MemberSearch, ops ...OpFunc) (*Member, error) {
+	obj := &Member{}
+	err := buildQuery(ctx, dr.db, obj, search, dr.filters[Tables.Member.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// MembersByFilters returns Member list.
+func (dr DirectoryRepo) MembersByFilters(ctx context.Context, search *MemberSearch, pager Pager, ops ...OpFunc) (members []Member, err error) {
+	err = buildQuery(ctx, dr.db, &members, search, dr.filters[Tables.Member.Name], pager, ops...).Select()
+	return
+}
+
+// CountMembers returns count
+func (dr DirectoryRepo) CountMembers(ctx context.Context, search *MemberSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, dr.db, &Member{}, search, dr.filters[Tables.Member.Name], PagerOne, ops...).Count()
+}
+
+// AddMember adds Member to DB.
+func (dr DirectoryRepo) AddMember(ctx context.Context, member *Member, ops ...OpFunc) (*Member, error) {
+	q := dr.db.ModelContext(ctx, member)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Member.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return member, err
+}
+
+// UpdateMember updates Member in DB.
+func (dr DirectoryRepo) UpdateMember(ctx context.Context, member *Member, ops ...OpFunc) (bool, error) {
+	q := dr.db.ModelContext(ctx, member).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Member.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteMember deletes Member from DB.
+func (dr DirectoryRepo) DeleteMember(ctx context.Context, id int) (deleted bool, err error) {
+	member := &Member{ID: id}
+
+	res, err := dr.db.ModelContext(ctx, member).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
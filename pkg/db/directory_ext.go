@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// SaveMember adds the member to the directory or replaces the entry of the same Telegram user.
+func (dr DirectoryRepo) SaveMember(ctx context.Context, member *Member) (*Member, error) {
+	member.UpdatedAt = time.Now()
+	q := dr.db.ModelContext(ctx, member).
+		ExcludeColumn(Columns.Member.ID, Columns.Member.CreatedAt).
+		OnConflict("(?) DO UPDATE", pg.Ident(Columns.Member.TgID))
+
+	for _, column := range []string{
+		Columns.Member.Username, Columns.Member.Role, Columns.Member.Name, Columns.Member.Year, Columns.Member.Class,
+		Columns.Member.Cities, Columns.Member.Universities, Columns.Member.Work, Columns.Member.Extra,
+		Columns.Member.ApplicationID, Columns.Member.UpdatedAt,
+	} {
+		q.Set("? = EXCLUDED.?", pg.Ident(column), pg.Ident(column))
+	}
+
+	_, err := q.Returning("*").Insert()
+	return member, err
+}
+
+// MemberByTgID returns directory entry of the Telegram user or nil.
+func (dr DirectoryRepo) MemberByTgID(ctx context.Context, tgID int64) (*Member, error) {
+	return dr.OneMember(ctx, &MemberSearch{TgID: &tgID})
+}
+
+// UnlistedApplications returns accepted applications of Telegram users missing in the directory in order of decision.
+// Accepted applications decided after decidedBefore are skipped as their decision could be undone,
+// applications accepted before the undo window was introduced have no decision time and are returned.
+func (dr DirectoryRepo) UnlistedApplications(ctx context.Context, decidedBefore time.Time) ([]Application, error) {
+	var list []Application
+	err := dr.db.ModelContext(ctx, &list).
+		Where("? in (?)", pg.Ident(Columns.Application.Status), pg.In([]string{ApplicationStatusAccepted, ApplicationStatusJoined})).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.
+				Where("? = ?", pg.Ident(Columns.Application.Status), ApplicationStatusJoined).
+				WhereOr("? is not null", pg.Ident(Columns.Application.NotifiedAt)).
+				WhereOr("? is null", pg.Ident(Columns.Application.DecidedAt)).
+				WhereOr("? < ?", pg.Ident(Columns.Application.DecidedAt), decidedBefore), nil
+		}).
+		Where("not exists (select 1 from ? m where m.? = ?.?)",
+			pg.Ident(Tables.Member.Name), pg.Ident(Columns.Member.TgID), pg.Ident(Tables.Application.Alias), pg.Ident(Columns.Application.TgID)).
+		OrderExpr("? asc, ? asc", pg.Ident(Columns.Application.DecidedAt), pg.Ident(Columns.Application.ID)).
+		Select()
+
+	return list, err
+}
+
+// MemberQuery is a directory search, values are matched case-insensitively by substring
+// and every word of the text should be found in name, cities, universities, work or extra of the member.
+// Members of any role are found if Roles are empty.
+type MemberQuery struct {
+	City       string
+	University string
+	Year       *int
+	Class      string
+	Words      []string
+	Roles      []string
+}
+
+// Search returns filters of the query.
+func (mq MemberQuery) Search() *MemberSearch {
+	col := func(column string) pg.Ident {
+		return pg.Ident(Tables.Member.Alias + "." + column)
+	}
+
+	search := &MemberSearch{Year: mq.Year, Roles: mq.Roles}
+	if mq.City != "" {
+		search.With("exists (select 1 from unnest(?) v where v ilike ?)", col(Columns.Member.Cities), containsPattern(mq.City))
+	}
+	if mq.University != "" {
+		search.With("exists (select 1 from unnest(?) v where v ilike ?)", col(Columns.Member.Universities), containsPattern(mq.University))
+	}
+	if mq.Class != "" {
+		search.With("? ilike ?", col(Columns.Member.Class), likeEscaper.Replace(mq.Class))
+	}
+	for _, word := range mq.Words {
+		search.With("concat_ws(' ', ?, ?, ?, array_to_string(?, ' '), array_to_string(?, ' ')) ilike ?",
+			col(Columns.Member.Name), col(Columns.Member.Work), col(Columns.Member.Extra),
+			col(Columns.Member.Cities), col(Columns.Member.Universities), containsPattern(word))
+	}
+
+	return search
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns like pattern matching the string anywhere.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// SearchMembers returns page of members found by the query sorted by name and their total count.
+func (dr DirectoryRepo) SearchMembers(ctx context.Context, query MemberQuery, pager Pager) ([]Member, int, error) {
+	count, err := dr.CountMembers(ctx, query.Search())
+	if err != nil || count == 0 {
+		return nil, count, err
+	}
+
+	list, err := dr.MembersByFilters(ctx, query.Search(), pager, WithSort(NewSortField(Columns.Member.Name, false), NewSortField(Columns.Member.ID, false)))
+	return list, count, err
+}
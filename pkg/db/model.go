@@ -5,6 +5,7 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -22,6 +23,51 @@ var Columns = struct {
 
 		ParentFolder string
 	}
+	Application struct {
+		ID, TgID, Role, Form, Status, AdminMessageID, CreatedAt, UpdatedAt, DecidedAt, RejectReason, NotifiedAt string
+	}
+	ModerationLog struct {
+		ID, ApplicationID, TargetTgID, ActorTgID, ActorName, Action, StatusBefore, StatusAfter, Comment, CreatedAt string
+
+		Application string
+	}
+	ApplicationVote struct {
+		ID, ApplicationID, VoterTgID, VoterName, Vote, Comment, CreatedAt string
+
+		Application string
+	}
+	InviteLink struct {
+		ID, ApplicationID, TgID, Link, ExpiresAt, CreatedAt, UsedAt, RevokedAt string
+
+		Application string
+	}
+	Conversation struct {
+		ID, TgID, Role, Step, Form, CreatedAt, UpdatedAt string
+	}
+	RegistrationToken struct {
+		ID, Token, TgID, Username, Role, ExpiresAt, CreatedAt, UsedAt string
+	}
+	OutboxMessage struct {
+		ID, Method, ChatID, Payload, ApplicationID, Kind, Status, Attempts, NextAttemptAt, LastError, CreatedAt, SentAt string
+
+		Application string
+	}
+	ProcessedUpdate struct {
+		ID, Payload, Status, ClaimedAt, Owner, HeartbeatAt, ProcessedAt string
+	}
+	Member struct {
+		ID, TgID, Username, Role, Name, Year, Class, Cities, Universities, Work, Extra, ApplicationID, CreatedAt, UpdatedAt string
+
+		Application string
+	}
+	Term struct {
+		ID, Kind, Name, Status, CreatedAt string
+	}
+	TermAlias struct {
+		ID, TermID, Kind, Alias, CreatedAt string
+
+		Term string
+	}
 }{
 	User: struct {
 		ID, CreatedAt, Login, Password, AuthKey, LastActivityAt, StatusID string
@@ -67,6 +113,168 @@ var Columns = struct {
 
 		ParentFolder: "ParentFolder",
 	},
+	Application: struct {
+		ID, TgID, Role, Form, Status, AdminMessageID, CreatedAt, UpdatedAt, DecidedAt, RejectReason, NotifiedAt string
+	}{
+		ID:             "applicationId",
+		TgID:           "tgId",
+		Role:           "role",
+		Form:           "form",
+		Status:         "status",
+		AdminMessageID: "adminMessageId",
+		CreatedAt:      "createdAt",
+		UpdatedAt:      "updatedAt",
+		DecidedAt:      "decidedAt",
+		RejectReason:   "rejectReason",
+		NotifiedAt:     "notifiedAt",
+	},
+	ModerationLog: struct {
+		ID, ApplicationID, TargetTgID, ActorTgID, ActorName, Action, StatusBefore, StatusAfter, Comment, CreatedAt string
+
+		Application string
+	}{
+		ID:            "moderationLogId",
+		ApplicationID: "applicationId",
+		TargetTgID:    "targetTgId",
+		ActorTgID:     "actorTgId",
+		ActorName:     "actorName",
+		Action:        "action",
+		StatusBefore:  "statusBefore",
+		StatusAfter:   "statusAfter",
+		Comment:       "comment",
+		CreatedAt:     "createdAt",
+
+		Application: "Application",
+	},
+	ApplicationVote: struct {
+		ID, ApplicationID, VoterTgID, VoterName, Vote, Comment, CreatedAt string
+
+		Application string
+	}{
+		ID:            "applicationVoteId",
+		ApplicationID: "applicationId",
+		VoterTgID:     "voterTgId",
+		VoterName:     "voterName",
+		Vote:          "vote",
+		Comment:       "comment",
+		CreatedAt:     "createdAt",
+
+		Application: "Application",
+	},
+	InviteLink: struct {
+		ID, ApplicationID, TgID, Link, ExpiresAt, CreatedAt, UsedAt, RevokedAt string
+
+		Application string
+	}{
+		ID:            "inviteLinkId",
+		ApplicationID: "applicationId",
+		TgID:          "tgId",
+		Link:          "link",
+		ExpiresAt:     "expiresAt",
+		CreatedAt:     "createdAt",
+		UsedAt:        "usedAt",
+		RevokedAt:     "revokedAt",
+
+		Application: "Application",
+	},
+	Conversation: struct {
+		ID, TgID, Role, Step, Form, CreatedAt, UpdatedAt string
+	}{
+		ID:        "conversationId",
+		TgID:      "tgId",
+		Role:      "role",
+		Step:      "step",
+		Form:      "form",
+		CreatedAt: "createdAt",
+		UpdatedAt: "updatedAt",
+	},
+	RegistrationToken: struct {
+		ID, Token, TgID, Username, Role, ExpiresAt, CreatedAt, UsedAt string
+	}{
+		ID:        "registrationTokenId",
+		Token:     "token",
+		TgID:      "tgId",
+		Username:  "username",
+		Role:      "role",
+		ExpiresAt: "expiresAt",
+		CreatedAt: "createdAt",
+		UsedAt:    "usedAt",
+	},
+	OutboxMessage: struct {
+		ID, Method, ChatID, Payload, ApplicationID, Kind, Status, Attempts, NextAttemptAt, LastError, CreatedAt, SentAt string
+
+		Application string
+	}{
+		ID:            "outboxMessageId",
+		Method:        "method",
+		ChatID:        "chatId",
+		Payload:       "payload",
+		ApplicationID: "applicationId",
+		Kind:          "kind",
+		Status:        "status",
+		Attempts:      "attempts",
+		NextAttemptAt: "nextAttemptAt",
+		LastError:     "lastError",
+		CreatedAt:     "createdAt",
+		SentAt:        "sentAt",
+
+		Application: "Application",
+	},
+	ProcessedUpdate: struct {
+		ID, Payload, Status, ClaimedAt, Owner, HeartbeatAt, ProcessedAt string
+	}{
+		ID:          "updateId",
+		Payload:     "payload",
+		Status:      "status",
+		ClaimedAt:   "claimedAt",
+		Owner:       "owner",
+		HeartbeatAt: "heartbeatAt",
+		ProcessedAt: "processedAt",
+	},
+	Member: struct {
+		ID, TgID, Username, Role, Name, Year, Class, Cities, Universities, Work, Extra, ApplicationID, CreatedAt, UpdatedAt string
+
+		Application string
+	}{
+		ID:            "memberId",
+		TgID:          "tgId",
+		Username:      "username",
+		Role:          "role",
+		Name:          "name",
+		Year:          "year",
+		Class:         "class",
+		Cities:        "cities",
+		Universities:  "universities",
+		Work:          "work",
+		Extra:         "extra",
+		ApplicationID: "applicationId",
+		CreatedAt:     "createdAt",
+		UpdatedAt:     "updatedAt",
+
+		Application: "Application",
+	},
+	Term: struct {
+		ID, Kind, Name, Status, CreatedAt string
+	}{
+		ID:        "termId",
+		Kind:      "kind",
+		Name:      "name",
+		Status:    "status",
+		CreatedAt: "createdAt",
+	},
+	TermAlias: struct {
+		ID, TermID, Kind, Alias, CreatedAt string
+
+		Term string
+	}{
+		ID:        "termAliasId",
+		TermID:    "termId",
+		Kind:      "kind",
+		Alias:     "alias",
+		CreatedAt: "createdAt",
+
+		Term: "Term",
+	},
 }
 
 var Tables = struct {
@@ -79,6 +287,39 @@ var Tables = struct {
 	VfsFolder struct {
 		Name, Alias string
 	}
+	Application struct {
+		Name, Alias string
+	}
+	ModerationLog struct {
+		Name, Alias string
+	}
+	ApplicationVote struct {
+		Name, Alias string
+	}
+	InviteLink struct {
+		Name, Alias string
+	}
+	Conversation struct {
+		Name, Alias string
+	}
+	RegistrationToken struct {
+		Name, Alias string
+	}
+	OutboxMessage struct {
+		Name, Alias string
+	}
+	ProcessedUpdate struct {
+		Name, Alias string
+	}
+	Member struct {
+		Name, Alias string
+	}
+	Term struct {
+		Name, Alias string
+	}
+	TermAlias struct {
+		Name, Alias string
+	}
 }{
 	User: struct {
 		Name, Alias string
@@ -98,6 +339,72 @@ var Tables = struct {
 		Name:  "vfsFolders",
 		Alias: "t",
 	},
+	Application: struct {
+		Name, Alias string
+	}{
+		Name:  "applications",
+		Alias: "t",
+	},
+	ModerationLog: struct {
+		Name, Alias string
+	}{
+		Name:  "moderationLogs",
+		Alias: "t",
+	},
+	ApplicationVote: struct {
+		Name, Alias string
+	}{
+		Name:  "applicationVotes",
+		Alias: "t",
+	},
+	InviteLink: struct {
+		Name, Alias string
+	}{
+		Name:  "inviteLinks",
+		Alias: "t",
+	},
+	Conversation: struct {
+		Name, Alias string
+	}{
+		Name:  "conversations",
+		Alias: "t",
+	},
+	RegistrationToken: struct {
+		Name, Alias string
+	}{
+		Name:  "registrationTokens",
+		Alias: "t",
+	},
+	OutboxMessage: struct {
+		Name, Alias string
+	}{
+		Name:  "outboxMessages",
+		Alias: "t",
+	},
+	ProcessedUpdate: struct {
+		Name, Alias string
+	}{
+		Name:  "processedUpdates",
+		Alias: "t",
+	},
+	Member: struct {
+		Name, Alias string
+	}{
+		Name:  "members",
+		Alias: "t",
+	},
+	Term: struct {
+		Name, Alias string
+	}{
+		Name:  "terms",
+		Alias: "t",
+	},
+	TermAlias: struct {
+		Name, Alias string
+	}{
+		Name:  "termAliases",
+		Alias: "t",
+	},
 }
 
 type User struct {
@@ -142,3 +449,164 @@ type VfsFolder struct {
 
 	ParentFolder *VfsFolder `pg:"fk:parentFolderId,rel:has-one"`
 }
+
+type Application struct {
+	tableName struct{} `pg:"applications,alias:t,discard_unknown_columns"`
+
+	ID             int             `pg:"applicationId,pk"`
+	TgID           int64           `pg:"tgId,use_zero"`
+	Role           string          `pg:"role,use_zero"`
+	Form           json.RawMessage `pg:"form,use_zero"`
+	Status         string          `pg:"status,use_zero"`
+	AdminMessageID *int            `pg:"adminMessageId"`
+	CreatedAt      time.Time       `pg:"createdAt,use_zero"`
+	UpdatedAt      time.Time       `pg:"updatedAt,use_zero"`
+	DecidedAt      *time.Time      `pg:"decidedAt"`
+	RejectReason   *string         `pg:"rejectReason"`
+	NotifiedAt     *time.Time      `pg:"notifiedAt"`
+}
+
+type ModerationLog struct {
+	tableName struct{} `pg:"moderationLogs,alias:t,discard_unknown_columns"`
+
+	ID            int       `pg:"moderationLogId,pk"`
+	ApplicationID *int      `pg:"applicationId"`
+	TargetTgID    int64     `pg:"targetTgId,use_zero"`
+	ActorTgID     int64     `pg:"actorTgId,use_zero"`
+	ActorName     string    `pg:"actorName,use_zero"`
+	Action        string    `pg:"action,use_zero"`
+	StatusBefore  *string   `pg:"statusBefore"`
+	StatusAfter   *string   `pg:"statusAfter"`
+	Comment       *string   `pg:"comment"`
+	CreatedAt     time.Time `pg:"createdAt,use_zero"`
+
+	Application *Application `pg:"fk:applicationId,rel:has-one"`
+}
+
+type ApplicationVote struct {
+	tableName struct{} `pg:"applicationVotes,alias:t,discard_unknown_columns"`
+
+	ID            int       `pg:"applicationVoteId,pk"`
+	ApplicationID int       `pg:"applicationId,use_zero"`
+	VoterTgID     int64     `pg:"voterTgId,use_zero"`
+	VoterName     string    `pg:"voterName,use_zero"`
+	Vote          string    `pg:"vote,use_zero"`
+	Comment       *string   `pg:"comment"`
+	CreatedAt     time.Time `pg:"createdAt,use_zero"`
+
+	Application *Application `pg:"fk:applicationId,rel:has-one"`
+}
+
+type InviteLink struct {
+	tableName struct{} `pg:"inviteLinks,alias:t,discard_unknown_columns"`
+
+	ID            int        `pg:"inviteLinkId,pk"`
+	ApplicationID int        `pg:"applicationId,use_zero"`
+	TgID          int64      `pg:"tgId,use_zero"`
+	Link          string     `pg:"link,use_zero"`
+	ExpiresAt     time.Time  `pg:"expiresAt,use_zero"`
+	CreatedAt     time.Time  `pg:"createdAt,use_zero"`
+	UsedAt        *time.Time `pg:"usedAt"`
+	RevokedAt     *time.Time `pg:"revokedAt"`
+
+	Application *Application `pg:"fk:applicationId,rel:has-one"`
+}
+
+type Conversation struct {
+	tableName struct{} `pg:"conversations,alias:t,discard_unknown_columns"`
+
+	ID        int             `pg:"conversationId,pk"`
+	TgID      int64           `pg:"tgId,use_zero"`
+	Role      string          `pg:"role,use_zero"`
+	Step      int             `pg:"step,use_zero"`
+	Form      json.RawMessage `pg:"form,use_zero"`
+	CreatedAt time.Time       `pg:"createdAt,use_zero"`
+	UpdatedAt time.Time       `pg:"updatedAt,use_zero"`
+}
+
+type RegistrationToken struct {
+	tableName struct{} `pg:"registrationTokens,alias:t,discard_unknown_columns"`
+
+	ID        int        `pg:"registrationTokenId,pk"`
+	Token     string     `pg:"token,use_zero"`
+	TgID      int64      `pg:"tgId,use_zero"`
+	Username  string     `pg:"username,use_zero"`
+	Role      string     `pg:"role,use_zero"`
+	ExpiresAt time.Time  `pg:"expiresAt,use_zero"`
+	CreatedAt time.Time  `pg:"createdAt,use_zero"`
+	UsedAt    *time.Time `pg:"usedAt"`
+}
+
+type OutboxMessage struct {
+	tableName struct{} `pg:"outboxMessages,alias:t,discard_unknown_columns"`
+
+	ID            int             `pg:"outboxMessageId,pk"`
+	Method        string          `pg:"method,use_zero"`
+	ChatID        int64           `pg:"chatId,use_zero"`
+	Payload       json.RawMessage `pg:"payload,use_zero"`
+	ApplicationID *int            `pg:"applicationId"`
+	Kind          *string         `pg:"kind"`
+	Status        string          `pg:"status,use_zero"`
+	Attempts      int             `pg:"attempts,use_zero"`
+	NextAttemptAt time.Time       `pg:"nextAttemptAt,use_zero"`
+	LastError     *string         `pg:"lastError"`
+	CreatedAt     time.Time       `pg:"createdAt,use_zero"`
+	SentAt        *time.Time      `pg:"sentAt"`
+
+	Application *Application `pg:"fk:applicationId,rel:has-one"`
+}
+
+type ProcessedUpdate struct {
+	tableName struct{} `pg:"processedUpdates,alias:t,discard_unknown_columns"`
+
+	ID          int64           `pg:"updateId,pk"`
+	Payload     json.RawMessage `pg:"payload,use_zero"`
+	Status      string          `pg:"status,use_zero"`
+	ClaimedAt   time.Time       `pg:"claimedAt,use_zero"`
+	Owner       string          `pg:"owner,use_zero"`
+	HeartbeatAt time.Time       `pg:"heartbeatAt,use_zero"`
+	ProcessedAt *time.Time      `pg:"processedAt"`
+}
+
+type Member struct {
+	tableName struct{} `pg:"members,alias:t,discard_unknown_columns"`
+
+	ID            int       `pg:"memberId,pk"`
+	TgID          int64     `pg:"tgId,use_zero"`
+	Username      *string   `pg:"username"`
+	Role          string    `pg:"role,use_zero"`
+	Name          string    `pg:"name,use_zero"`
+	Year          *int      `pg:"year"`
+	Class         *string   `pg:"class"`
+	Cities        []string  `pg:"cities,array"`
+	Universities  []string  `pg:"universities,array"`
+	Work          *string   `pg:"work"`
+	Extra         *string   `pg:"extra"`
+	ApplicationID *int      `pg:"applicationId"`
+	CreatedAt     time.Time `pg:"createdAt,use_zero"`
+	UpdatedAt     time.Time `pg:"updatedAt,use_zero"`
+
+	Application *Application `pg:"fk:applicationId,rel:has-one"`
+}
+
+type Term struct {
+	tableName struct{} `pg:"terms,alias:t,discard_unknown_columns"`
+
+	ID        int       `pg:"termId,pk"`
+	Kind      string    `pg:"kind,use_zero"`
+	Name      string    `pg:"name,use_zero"`
+	Status    string    `pg:"status,use_zero"`
+	CreatedAt time.Time `pg:"createdAt,use_zero"`
+}
+
+type TermAlias struct {
+	tableName struct{} `pg:"termAliases,alias:t,discard_unknown_columns"`
+
+	ID        int       `pg:"termAliasId,pk"`
+	TermID    int       `pg:"termId,use_zero"`
+	Kind      string    `pg:"kind,use_zero"`
+	Alias     string    `pg:"alias,use_zero"`
+	CreatedAt time.Time `pg:"createdAt,use_zero"`
+
+	Term *Term `pg:"fk:termId,rel:has-one"`
+}
@@ -275,3 +275,775 @@ func (vfs *VfsFolderSearch) Q() applier {
 		return vfs.Apply(query), nil
 	}
 }
+
+type ApplicationSearch struct {
+	search
+
+	ID             *int
+	TgID           *int64
+	Role           *string
+	Status         *string
+	AdminMessageID *int
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+	DecidedAt      *time.Time
+	RejectReason   *string
+	NotifiedAt     *time.Time
+	IDs            []int
+	Statuses       []string
+	CreatedAtFrom  *time.Time
+	CreatedAtTo    *time.Time
+	DecidedAtTo    *time.Time
+	NotifiedAtNull *bool
+}
+
+func (as *ApplicationSearch) Apply(query *orm.Query) *orm.Query {
+	if as == nil {
+		return query
+	}
+	if as.ID != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.ID, as.ID)
+	}
+	if as.TgID != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.TgID, as.TgID)
+	}
+	if as.Role != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.Role, as.Role)
+	}
+	if as.Status != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.Status, as.Status)
+	}
+	if as.AdminMessageID != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.AdminMessageID, as.AdminMessageID)
+	}
+	if as.CreatedAt != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.CreatedAt, as.CreatedAt)
+	}
+	if as.UpdatedAt != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.UpdatedAt, as.UpdatedAt)
+	}
+	if as.DecidedAt != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.DecidedAt, as.DecidedAt)
+	}
+	if as.RejectReason != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.RejectReason, as.RejectReason)
+	}
+	if as.NotifiedAt != nil {
+		as.where(query, Tables.Application.Alias, Columns.Application.NotifiedAt, as.NotifiedAt)
+	}
+	if len(as.IDs) > 0 {
+		Filter{Columns.Application.ID, as.IDs, SearchTypeArray, false}.Apply(query)
+	}
+	if len(as.Statuses) > 0 {
+		Filter{Columns.Application.Status, as.Statuses, SearchTypeArray, false}.Apply(query)
+	}
+	if as.CreatedAtFrom != nil {
+		Filter{Columns.Application.CreatedAt, *as.CreatedAtFrom, SearchTypeGE, false}.Apply(query)
+	}
+	if as.CreatedAtTo != nil {
+		Filter{Columns.Application.CreatedAt, *as.CreatedAtTo, SearchTypeLE, false}.Apply(query)
+	}
+	if as.DecidedAtTo != nil {
+		Filter{Columns.Application.DecidedAt, *as.DecidedAtTo, SearchTypeLE, false}.Apply(query)
+	}
+	if as.NotifiedAtNull != nil {
+		Filter{Columns.Application.NotifiedAt, nil, SearchTypeNull, !*as.NotifiedAtNull}.Apply(query)
+	}
+
+	as.apply(query)
+
+	return query
+}
+
+func (as *ApplicationSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if as == nil {
+			return query, nil
+		}
+		return as.Apply(query), nil
+	}
+}
+
+type ModerationLogSearch struct {
+	search
+
+	ID             *int
+	ApplicationID  *int
+	TargetTgID     *int64
+	ActorTgID      *int64
+	ActorName      *string
+	Action         *string
+	StatusBefore   *string
+	StatusAfter    *string
+	Comment        *string
+	CreatedAt      *time.Time
+	IDs            []int
+	Actions        []string
+	ActorNameILike *string
+	CreatedAtFrom  *time.Time
+	CreatedAtTo    *time.Time
+}
+
+func (mls *ModerationLogSearch) Apply(query *orm.Query) *orm.Query {
+	if mls == nil {
+		return query
+	}
+	if mls.ID != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.ID, mls.ID)
+	}
+	if mls.ApplicationID != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.ApplicationID, mls.ApplicationID)
+	}
+	if mls.TargetTgID != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.TargetTgID, mls.TargetTgID)
+	}
+	if mls.ActorTgID != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.ActorTgID, mls.ActorTgID)
+	}
+	if mls.ActorName != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.ActorName, mls.ActorName)
+	}
+	if mls.Action != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.Action, mls.Action)
+	}
+	if mls.StatusBefore != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.StatusBefore, mls.StatusBefore)
+	}
+	if mls.StatusAfter != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.StatusAfter, mls.StatusAfter)
+	}
+	if mls.Comment != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.Comment, mls.Comment)
+	}
+	if mls.CreatedAt != nil {
+		mls.where(query, Tables.ModerationLog.Alias, Columns.ModerationLog.CreatedAt, mls.CreatedAt)
+	}
+	if len(mls.IDs) > 0 {
+		Filter{Columns.ModerationLog.ID, mls.IDs, SearchTypeArray, false}.Apply(query)
+	}
+	if len(mls.Actions) > 0 {
+		Filter{Columns.ModerationLog.Action, mls.Actions, SearchTypeArray, false}.Apply(query)
+	}
+	if mls.ActorNameILike != nil {
+		Filter{Columns.ModerationLog.ActorName, *mls.ActorNameILike, SearchTypeILike, false}.Apply(query)
+	}
+	if mls.CreatedAtFrom != nil {
+		Filter{Columns.ModerationLog.CreatedAt, *mls.CreatedAtFrom, SearchTypeGE, false}.Apply(query)
+	}
+	if mls.CreatedAtTo != nil {
+		Filter{Columns.ModerationLog.CreatedAt, *mls.CreatedAtTo, SearchTypeLE, false}.Apply(query)
+	}
+
+	mls.apply(query)
+
+	return query
+}
+
+func (mls *ModerationLogSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if mls == nil {
+			return query, nil
+		}
+		return mls.Apply(query), nil
+	}
+}
+
+type ApplicationVoteSearch struct {
+	search
+
+	ID            *int
+	ApplicationID *int
+	VoterTgID     *int64
+	VoterName     *string
+	Vote          *string
+	Comment       *string
+	CreatedAt     *time.Time
+	IDs           []int
+}
+
+func (avs *ApplicationVoteSearch) Apply(query *orm.Query) *orm.Query {
+	if avs == nil {
+		return query
+	}
+	if avs.ID != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.ID, avs.ID)
+	}
+	if avs.ApplicationID != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.ApplicationID, avs.ApplicationID)
+	}
+	if avs.VoterTgID != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.VoterTgID, avs.VoterTgID)
+	}
+	if avs.VoterName != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.VoterName, avs.VoterName)
+	}
+	if avs.Vote != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.Vote, avs.Vote)
+	}
+	if avs.Comment != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.Comment, avs.Comment)
+	}
+	if avs.CreatedAt != nil {
+		avs.where(query, Tables.ApplicationVote.Alias, Columns.ApplicationVote.CreatedAt, avs.CreatedAt)
+	}
+	if len(avs.IDs) > 0 {
+		Filter{Columns.ApplicationVote.ID, avs.IDs, SearchTypeArray, false}.Apply(query)
+	}
+
+	avs.apply(query)
+
+	return query
+}
+
+func (avs *ApplicationVoteSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if avs == nil {
+			return query, nil
+		}
+		return avs.Apply(query), nil
+	}
+}
+
+type InviteLinkSearch struct {
+	search
+
+	ID            *int
+	ApplicationID *int
+	TgID          *int64
+	Link          *string
+	ExpiresAt     *time.Time
+	CreatedAt     *time.Time
+	UsedAt        *time.Time
+	RevokedAt     *time.Time
+	IDs           []int
+	ExpiresAtFrom *time.Time
+	ExpiresAtTo   *time.Time
+	UsedAtNull    *bool
+	RevokedAtNull *bool
+}
+
+func (ils *InviteLinkSearch) Apply(query *orm.Query) *orm.Query {
+	if ils == nil {
+		return query
+	}
+	if ils.ID != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.ID, ils.ID)
+	}
+	if ils.ApplicationID != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.ApplicationID, ils.ApplicationID)
+	}
+	if ils.TgID != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.TgID, ils.TgID)
+	}
+	if ils.Link != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.Link, ils.Link)
+	}
+	if ils.ExpiresAt != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.ExpiresAt, ils.ExpiresAt)
+	}
+	if ils.CreatedAt != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.CreatedAt, ils.CreatedAt)
+	}
+	if ils.UsedAt != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.UsedAt, ils.UsedAt)
+	}
+	if ils.RevokedAt != nil {
+		ils.where(query, Tables.InviteLink.Alias, Columns.InviteLink.RevokedAt, ils.RevokedAt)
+	}
+	if len(ils.IDs) > 0 {
+		Filter{Columns.InviteLink.ID, ils.IDs, SearchTypeArray, false}.Apply(query)
+	}
+	if ils.ExpiresAtFrom != nil {
+		Filter{Columns.InviteLink.ExpiresAt, *ils.ExpiresAtFrom, SearchTypeGE, false}.Apply(query)
+	}
+	if ils.ExpiresAtTo != nil {
+		Filter{Columns.InviteLink.ExpiresAt, *ils.ExpiresAtTo, SearchTypeLE, false}.Apply(query)
+	}
+	if ils.UsedAtNull != nil {
+		Filter{Columns.InviteLink.UsedAt, nil, SearchTypeNull, !*ils.UsedAtNull}.Apply(query)
+	}
+	if ils.RevokedAtNull != nil {
+		Filter{Columns.InviteLink.RevokedAt, nil, SearchTypeNull, !*ils.RevokedAtNull}.Apply(query)
+	}
+
+	ils.apply(query)
+
+	return query
+}
+
+func (ils *InviteLinkSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if ils == nil {
+			return query, nil
+		}
+		return ils.Apply(query), nil
+	}
+}
+
+type ConversationSearch struct {
+	search
+
+	ID        *int
+	TgID      *int64
+	Role      *string
+	Step      *int
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+	IDs       []int
+}
+
+func (cs *ConversationSearch) Apply(query *orm.Query) *orm.Query {
+	if cs == nil {
+		return query
+	}
+	if cs.ID != nil {
+		cs.where(query, Tables.Conversation.Alias, Columns.Conversation.ID, cs.ID)
+	}
+	if cs.TgID != nil {
+		cs.where(query, Tables.Conversation.Alias, Columns.Conversation.TgID, cs.TgID)
+	}
+	if cs.Role != nil {
+		cs.where(query, Tables.Conversation.Alias, Columns.Conversation.Role, cs.Role)
+	}
+	if cs.Step != nil {
+		cs.where(query, Tables.Conversation.Alias, Columns.Conversation.Step, cs.Step)
+	}
+	if cs.CreatedAt != nil {
+		cs.where(query, Tables.Conversation.Alias, Columns.Conversation.CreatedAt, cs.CreatedAt)
+	}
+	if cs.UpdatedAt != nil {
+		cs.where(query, Tables.Conversation.Alias, Columns.Conversation.UpdatedAt, cs.UpdatedAt)
+	}
+	if len(cs.IDs) > 0 {
+		Filter{Columns.Conversation.ID, cs.IDs, SearchTypeArray, false}.Apply(query)
+	}
+
+	cs.apply(query)
+
+	return query
+}
+
+func (cs *ConversationSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if cs == nil {
+			return query, nil
+		}
+		return cs.Apply(query), nil
+	}
+}
+
+type RegistrationTokenSearch struct {
+	search
+
+	ID        *int
+	Token     *string
+	TgID      *int64
+	Username  *string
+	Role      *string
+	ExpiresAt *time.Time
+	CreatedAt *time.Time
+	UsedAt    *time.Time
+	IDs       []int
+}
+
+func (rts *RegistrationTokenSearch) Apply(query *orm.Query) *orm.Query {
+	if rts == nil {
+		return query
+	}
+	if rts.ID != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.ID, rts.ID)
+	}
+	if rts.Token != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.Token, rts.Token)
+	}
+	if rts.TgID != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.TgID, rts.TgID)
+	}
+	if rts.Username != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.Username, rts.Username)
+	}
+	if rts.Role != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.Role, rts.Role)
+	}
+	if rts.ExpiresAt != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.ExpiresAt, rts.ExpiresAt)
+	}
+	if rts.CreatedAt != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.CreatedAt, rts.CreatedAt)
+	}
+	if rts.UsedAt != nil {
+		rts.where(query, Tables.RegistrationToken.Alias, Columns.RegistrationToken.UsedAt, rts.UsedAt)
+	}
+	if len(rts.IDs) > 0 {
+		Filter{Columns.RegistrationToken.ID, rts.IDs, SearchTypeArray, false}.Apply(query)
+	}
+
+	rts.apply(query)
+
+	return query
+}
+
+func (rts *RegistrationTokenSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if rts == nil {
+			return query, nil
+		}
+		return rts.Apply(query), nil
+	}
+}
+
+type OutboxMessageSearch struct {
+	search
+
+	ID              *int
+	Method          *string
+	ChatID          *int64
+	ApplicationID   *int
+	Kind            *string
+	Status          *string
+	Attempts        *int
+	NextAttemptAt   *time.Time
+	LastError       *string
+	CreatedAt       *time.Time
+	SentAt          *time.Time
+	Statuses        []string
+	NextAttemptAtTo *time.Time
+	CreatedAtFrom   *time.Time
+	CreatedAtTo     *time.Time
+}
+
+func (oms *OutboxMessageSearch) Apply(query *orm.Query) *orm.Query {
+	if oms == nil {
+		return query
+	}
+	if oms.ID != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.ID, oms.ID)
+	}
+	if oms.Method != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.Method, oms.Method)
+	}
+	if oms.ChatID != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.ChatID, oms.ChatID)
+	}
+	if oms.ApplicationID != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.ApplicationID, oms.ApplicationID)
+	}
+	if oms.Kind != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.Kind, oms.Kind)
+	}
+	if oms.Status != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.Status, oms.Status)
+	}
+	if oms.Attempts != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.Attempts, oms.Attempts)
+	}
+	if oms.NextAttemptAt != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.NextAttemptAt, oms.NextAttemptAt)
+	}
+	if oms.LastError != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.LastError, oms.LastError)
+	}
+	if oms.CreatedAt != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.CreatedAt, oms.CreatedAt)
+	}
+	if oms.SentAt != nil {
+		oms.where(query, Tables.OutboxMessage.Alias, Columns.OutboxMessage.SentAt, oms.SentAt)
+	}
+	if len(oms.Statuses) > 0 {
+		Filter{Columns.OutboxMessage.Status, oms.Statuses, SearchTypeArray, false}.Apply(query)
+	}
+	if oms.NextAttemptAtTo != nil {
+		Filter{Columns.OutboxMessage.NextAttemptAt, *oms.NextAttemptAtTo, SearchTypeLE, false}.Apply(query)
+	}
+	if oms.CreatedAtFrom != nil {
+		Filter{Columns.OutboxMessage.CreatedAt, *oms.CreatedAtFrom, SearchTypeGE, false}.Apply(query)
+	}
+	if oms.CreatedAtTo != nil {
+		Filter{Columns.OutboxMessage.CreatedAt, *oms.CreatedAtTo, SearchTypeLE, false}.Apply(query)
+	}
+
+	oms.apply(query)
+
+	return query
+}
+
+func (oms *OutboxMessageSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if oms == nil {
+			return query, nil
+		}
+		return oms.Apply(query), nil
+	}
+}
+
+type ProcessedUpdateSearch struct {
+	search
+
+	ID            *int64
+	Status        *string
+	ClaimedAt     *time.Time
+	Owner         *string
+	HeartbeatAt   *time.Time
+	ProcessedAt   *time.Time
+	Statuses      []string
+	ClaimedAtTo   *time.Time
+	HeartbeatAtTo *time.Time
+	ProcessedAtTo *time.Time
+}
+
+func (pus *ProcessedUpdateSearch) Apply(query *orm.Query) *orm.Query {
+	if pus == nil {
+		return query
+	}
+	if pus.ID != nil {
+		pus.where(query, Tables.ProcessedUpdate.Alias, Columns.ProcessedUpdate.ID, pus.ID)
+	}
+	if pus.Status != nil {
+		pus.where(query, Tables.ProcessedUpdate.Alias, Columns.ProcessedUpdate.Status, pus.Status)
+	}
+	if pus.ClaimedAt != nil {
+		pus.where(query, Tables.ProcessedUpdate.Alias, Columns.ProcessedUpdate.ClaimedAt, pus.ClaimedAt)
+	}
+	if pus.Owner != nil {
+		pus.where(query, Tables.ProcessedUpdate.Alias, Columns.ProcessedUpdate.Owner, pus.Owner)
+	}
+	if pus.HeartbeatAt != nil {
+		pus.where(query, Tables.ProcessedUpdate.Alias, Columns.ProcessedUpdate.HeartbeatAt, pus.HeartbeatAt)
+	}
+	if pus.ProcessedAt != nil {
+		pus.where(query, Tables.ProcessedUpdate.Alias, Columns.ProcessedUpdate.ProcessedAt, pus.ProcessedAt)
+	}
+	if len(pus.Statuses) > 0 {
+		Filter{Columns.ProcessedUpdate.Status, pus.Statuses, SearchTypeArray, false}.Apply(query)
+	}
+	if pus.ClaimedAtTo != nil {
+		Filter{Columns.ProcessedUpdate.ClaimedAt, *pus.ClaimedAtTo, SearchTypeLE, false}.Apply(query)
+	}
+	if pus.HeartbeatAtTo != nil {
+		Filter{Columns.ProcessedUpdate.HeartbeatAt, *pus.HeartbeatAtTo, SearchTypeLE, false}.Apply(query)
+	}
+	if pus.ProcessedAtTo != nil {
+		Filter{Columns.ProcessedUpdate.ProcessedAt, *pus.ProcessedAtTo, SearchTypeLE, false}.Apply(query)
+	}
+
+	pus.apply(query)
+
+	return query
+}
+
+func (pus *ProcessedUpdateSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if pus == nil {
+			return query, nil
+		}
+		return pus.Apply(query), nil
+	}
+}
+
+type MemberSearch struct {
+	search
+
+	ID            *int
+	TgID          *int64
+	Username      *string
+	Role          *string
+	Name          *string
+	Year          *int
+	Class         *string
+	Work          *string
+	Extra         *string
+	ApplicationID *int
+	CreatedAt     *time.Time
+	UpdatedAt     *time.Time
+	IDs           []int
+	TgIDs         []int64
+	Roles         []string
+	NameILike     *string
+	City          *string
+	University    *string
+	WorkILike     *string
+}
+
+func (ms *MemberSearch) Apply(query *orm.Query) *orm.Query {
+	if ms == nil {
+		return query
+	}
+	if ms.ID != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.ID, ms.ID)
+	}
+	if ms.TgID != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.TgID, ms.TgID)
+	}
+	if ms.Username != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Username, ms.Username)
+	}
+	if ms.Role != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Role, ms.Role)
+	}
+	if ms.Name != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Name, ms.Name)
+	}
+	if ms.Year != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Year, ms.Year)
+	}
+	if ms.Class != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Class, ms.Class)
+	}
+	if ms.Work != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Work, ms.Work)
+	}
+	if ms.Extra != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.Extra, ms.Extra)
+	}
+	if ms.ApplicationID != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.ApplicationID, ms.ApplicationID)
+	}
+	if ms.CreatedAt != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.CreatedAt, ms.CreatedAt)
+	}
+	if ms.UpdatedAt != nil {
+		ms.where(query, Tables.Member.Alias, Columns.Member.UpdatedAt, ms.UpdatedAt)
+	}
+	if len(ms.IDs) > 0 {
+		Filter{Columns.Member.ID, ms.IDs, SearchTypeArray, false}.Apply(query)
+	}
+	if len(ms.TgIDs) > 0 {
+		Filter{Columns.Member.TgID, ms.TgIDs, SearchTypeArray, false}.Apply(query)
+	}
+	if len(ms.Roles) > 0 {
+		Filter{Columns.Member.Role, ms.Roles, SearchTypeArray, false}.Apply(query)
+	}
+	if ms.NameILike != nil {
+		Filter{Columns.Member.Name, *ms.NameILike, SearchTypeILike, false}.Apply(query)
+	}
+	if ms.City != nil {
+		Filter{Columns.Member.Cities, *ms.City, SearchTypeArrayContains, false}.Apply(query)
+	}
+	if ms.University != nil {
+		Filter{Columns.Member.Universities, *ms.University, SearchTypeArrayContains, false}.Apply(query)
+	}
+	if ms.WorkILike != nil {
+		Filter{Columns.Member.Work, *ms.WorkILike, SearchTypeILike, false}.Apply(query)
+	}
+
+	ms.apply(query)
+
+	return query
+}
+
+func (ms *MemberSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if ms == nil {
+			return query, nil
+		}
+		return ms.Apply(query), nil
+	}
+}
+
+type TermSearch struct {
+	search
+
+	ID        *int
+	Kind      *string
+	Name      *string
+	Status    *string
+	CreatedAt *time.Time
+	IDs       []int
+	Statuses  []string
+	NameILike *string
+}
+
+func (ts *TermSearch) Apply(query *orm.Query) *orm.Query {
+	if ts == nil {
+		return query
+	}
+	if ts.ID != nil {
+		ts.where(query, Tables.Term.Alias, Columns.Term.ID, ts.ID)
+	}
+	if ts.Kind != nil {
+		ts.where(query, Tables.Term.Alias, Columns.Term.Kind, ts.Kind)
+	}
+	if ts.Name != nil {
+		ts.where(query, Tables.Term.Alias, Columns.Term.Name, ts.Name)
+	}
+	if ts.Status != nil {
+		ts.where(query, Tables.Term.Alias, Columns.Term.Status, ts.Status)
+	}
+	if ts.CreatedAt != nil {
+		ts.where(query, Tables.Term.Alias, Columns.Term.CreatedAt, ts.CreatedAt)
+	}
+	if len(ts.IDs) > 0 {
+		Filter{Columns.Term.ID, ts.IDs, SearchTypeArray, false}.Apply(query)
+	}
+	if len(ts.Statuses) > 0 {
+		Filter{Columns.Term.Status, ts.Statuses, SearchTypeArray, false}.Apply(query)
+	}
+	if ts.NameILike != nil {
+		Filter{Columns.Term.Name, *ts.NameILike, SearchTypeILike, false}.Apply(query)
+	}
+
+	ts.apply(query)
+
+	return query
+}
+
+func (ts *TermSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if ts == nil {
+			return query, nil
+		}
+		return ts.Apply(query), nil
+	}
+}
+
+type TermAliasSearch struct {
+	search
+
+	ID        *int
+	TermID    *int
+	Kind      *string
+	Alias     *string
+	CreatedAt *time.Time
+	IDs       []int
+	TermIDs   []int
+}
+
+func (tas *TermAliasSearch) Apply(query *orm.Query) *orm.Query {
+	if tas == nil {
+		return query
+	}
+	if tas.ID != nil {
+		tas.where(query, Tables.TermAlias.Alias, Columns.TermAlias.ID, tas.ID)
+	}
+	if tas.TermID != nil {
+		tas.where(query, Tables.TermAlias.Alias, Columns.TermAlias.TermID, tas.TermID)
+	}
+	if tas.Kind != nil {
+		tas.where(query, Tables.TermAlias.Alias, Columns.TermAlias.Kind, tas.Kind)
+	}
+	if tas.Alias != nil {
+		tas.where(query, Tables.TermAlias.Alias, Columns.TermAlias.Alias, tas.Alias)
+	}
+	if tas.CreatedAt != nil {
+		tas.where(query, Tables.TermAlias.Alias, Columns.TermAlias.CreatedAt, tas.CreatedAt)
+	}
+	if len(tas.IDs) > 0 {
+		Filter{Columns.TermAlias.ID, tas.IDs, SearchTypeArray, false}.Apply(query)
+	}
+	if len(tas.TermIDs) > 0 {
+		Filter{Columns.TermAlias.TermID, tas.TermIDs, SearchTypeArray, false}.Apply(query)
+	}
+
+	tas.apply(query)
+
+	return query
+}
+
+func (tas *TermAliasSearch) Q() applier {
+	return func(query *orm.Query) (*orm.Query, error) {
+		if tas == nil {
+			return query, nil
+		}
+		return tas.Apply(query), nil
+	}
+}
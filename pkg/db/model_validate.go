@@ -59,3 +59,177 @@ func (vf VfsFolder) Validate() (errors map[string]string, valid bool) {
 
 	return errors, len(errors) == 0
 }
+
+func (a Application) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(a.Role) > 32 {
+		errors[Columns.Application.Role] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(a.Status) > 16 {
+		errors[Columns.Application.Status] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (ml ModerationLog) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(ml.ActorName) > 255 {
+		errors[Columns.ModerationLog.ActorName] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(ml.Action) > 16 {
+		errors[Columns.ModerationLog.Action] = ErrMaxLength
+	}
+
+	if ml.StatusBefore != nil && utf8.RuneCountInString(*ml.StatusBefore) > 16 {
+		errors[Columns.ModerationLog.StatusBefore] = ErrMaxLength
+	}
+
+	if ml.StatusAfter != nil && utf8.RuneCountInString(*ml.StatusAfter) > 16 {
+		errors[Columns.ModerationLog.StatusAfter] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (av ApplicationVote) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(av.VoterName) > 255 {
+		errors[Columns.ApplicationVote.VoterName] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(av.Vote) > 16 {
+		errors[Columns.ApplicationVote.Vote] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (il InviteLink) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(il.Link) > 255 {
+		errors[Columns.InviteLink.Link] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (c Conversation) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(c.Role) > 32 {
+		errors[Columns.Conversation.Role] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (rt RegistrationToken) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(rt.Token) > 64 {
+		errors[Columns.RegistrationToken.Token] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(rt.Username) > 255 {
+		errors[Columns.RegistrationToken.Username] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(rt.Role) > 32 {
+		errors[Columns.RegistrationToken.Role] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (om OutboxMessage) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(om.Method) > 32 {
+		errors[Columns.OutboxMessage.Method] = ErrMaxLength
+	}
+
+	if om.Kind != nil && utf8.RuneCountInString(*om.Kind) > 32 {
+		errors[Columns.OutboxMessage.Kind] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(om.Status) > 16 {
+		errors[Columns.OutboxMessage.Status] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (pu ProcessedUpdate) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(pu.Status) > 16 {
+		errors[Columns.ProcessedUpdate.Status] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(pu.Owner) > 64 {
+		errors[Columns.ProcessedUpdate.Owner] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (m Member) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if m.Username != nil && utf8.RuneCountInString(*m.Username) > 64 {
+		errors[Columns.Member.Username] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(m.Role) > 32 {
+		errors[Columns.Member.Role] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(m.Name) > 255 {
+		errors[Columns.Member.Name] = ErrMaxLength
+	}
+
+	if m.Class != nil && utf8.RuneCountInString(*m.Class) > 8 {
+		errors[Columns.Member.Class] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (t Term) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(t.Kind) > 16 {
+		errors[Columns.Term.Kind] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(t.Name) > 255 {
+		errors[Columns.Term.Name] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(t.Status) > 16 {
+		errors[Columns.Term.Status] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (ta TermAlias) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(ta.Kind) > 16 {
+		errors[Columns.TermAlias.Kind] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(ta.Alias) > 255 {
+		errors[Columns.TermAlias.Alias] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
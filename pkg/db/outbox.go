@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+type OutboxRepo struct {
+	db      orm.DB
+	filters map[string][]Filter
+	sort    map[string][]SortField
+	join    map[string][]string
+}
+
+// NewOutboxRepo returns new repository
+func NewOutboxRepo(db orm.DB) OutboxRepo {
+	return OutboxRepo{
+		db: db,
+		filters: map[string][]Filter{
+			Tables.OutboxMessage.Name: {},
+		},
+		sort: map[string][]SortField{
+			Tables.OutboxMessage.Name: {{Column: Columns.OutboxMessage.ID, Direction: SortAsc}},
+		},
+		join: map[string][]string{
+			Tables.OutboxMessage.Name: {TableColumns, Columns.OutboxMessage.Application},
+		},
+	}
+}
+
+// WithTransaction is a function that wraps OutboxRepo with pg.Tx transaction.
+func (or OutboxRepo) WithTransaction(tx *pg.Tx) OutboxRepo {
+	or.db = tx
+	return or
+}
+
+/*** OutboxMessage ***/
+
+// FullOutboxMessage returns full joins with all columns
+func (or OutboxRepo) FullOutboxMessage() OpFunc {
+	return WithColumns(or.join[Tables.OutboxMessage.Name]...)
+}
+
+// DefaultOutboxMessageSort returns default sort.
+func (or OutboxRepo) DefaultOutboxMessageSort() OpFunc {
+	return WithSort(or.sort[Tables.OutboxMessage.Name]...)
+}
+
+// OutboxMessageByID is a function that returns OutboxMessage by ID(s) or nil.
+func (or OutboxRepo) OutboxMessageByID(ctx context.Context, id int, ops ...OpFunc) (*OutboxMessage, error) {
+	return or.OneOutboxMessage(ctx, &OutboxMessageSearch{ID: &id}, ops...)
+}
+
+// OneOutboxMessage is a function that returns one OutboxMessage by filters. It could return pg.ErrMultiRows.
+func (or OutboxRepo) OneOutboxMessage(ctx context.Context, search *OutboxMessageSearch, ops ...OpFunc) (*OutboxMessage, error) {
+	obj := &OutboxMessage{}
+	err := buildQuery(ctx, or.db, obj, search, or.filters[Tables.OutboxMessage.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// OutboxMessagesByFilters returns OutboxMessage list.
+func (or OutboxRepo) OutboxMessagesByFilters(ctx context.Context, search *OutboxMessageSearch, pager Pager, ops ...OpFunc) (outboxMessages []OutboxMessage, err error) {
+	err = buildQuery(ctx, or.db, &outboxMessages, search, or.filters[Tables.OutboxMessage.Name], pager, ops...).Select()
+	return
+}
+
+// CountOutboxMessages returns count
+func (or OutboxRepo) CountOutboxMessages(ctx context.Context, search *OutboxMessageSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, or.db, &OutboxMessage{}, search, or.filters[Tables.OutboxMessage.Name], PagerOne, ops...).Count()
+}
+
+// AddOutboxMessage adds OutboxMessage to DB.
+func (or OutboxRepo) AddOutboxMessage(ctx context.Context, outboxMessage *OutboxMessage, ops ...OpFunc) (*OutboxMessage, error) {
+	q := or.db.ModelContext(ctx, outboxMessage)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.OutboxMessage.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return outboxMessage, err
+}
+
+// UpdateOutboxMessage updates OutboxMessage in DB.
+func (or OutboxRepo) UpdateOutboxMessage(ctx context.Context, outboxMessage *OutboxMessage, ops ...OpFunc) (bool, error) {
+	q := or.db.ModelContext(ctx, outboxMessage).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.OutboxMessage.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteOutboxMessage deletes OutboxMessage from DB.
+func (or OutboxRepo) DeleteOutboxMessage(ctx context.Context, id int) (deleted bool, err error) {
+	outboxMessage := &OutboxMessage{ID: id}
+
+	res, err := or.db.ModelContext(ctx, outboxMessage).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
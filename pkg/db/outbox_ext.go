@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+const (
+	// outbox message statuses
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusDead    = "dead"
+)
+
+// DueOutboxMessages returns pending messages whose next attempt time has come in order of queueing, messages to the skipped chats are not returned.
+// Messages queued after a pending message of the same chat which is not due yet are held back to keep the order of the chat.
+func (or OutboxRepo) DueOutboxMessages(ctx context.Context, limit int, skipChats []int64) ([]OutboxMessage, error) {
+	now := time.Now()
+	search := &OutboxMessageSearch{
+		Statuses:        []string{OutboxStatusPending},
+		NextAttemptAtTo: &now,
+	}
+	search.With("not exists (select 1 from ? as prev where ? = ? and ? = ? and ? < ? and ? > ?)",
+		pg.Ident(Tables.OutboxMessage.Name),
+		pg.Ident("prev."+Columns.OutboxMessage.ChatID), pg.Ident(Tables.OutboxMessage.Alias+"."+Columns.OutboxMessage.ChatID),
+		pg.Ident("prev."+Columns.OutboxMessage.Status), OutboxStatusPending,
+		pg.Ident("prev."+Columns.OutboxMessage.ID), pg.Ident(Tables.OutboxMessage.Alias+"."+Columns.OutboxMessage.ID),
+		pg.Ident("prev."+Columns.OutboxMessage.NextAttemptAt), now)
+	if len(skipChats) > 0 {
+		search.With("? not in (?)", pg.Ident(Tables.OutboxMessage.Alias+"."+Columns.OutboxMessage.ChatID), pg.In(skipChats))
+	}
+
+	return or.OutboxMessagesByFilters(ctx, search, Pager{Page: 1, PageSize: limit}, or.DefaultOutboxMessageSort())
+}
+
+// SetOutboxMessageSent marks the message as delivered.
+func (or OutboxRepo) SetOutboxMessageSent(ctx context.Context, msg *OutboxMessage) (bool, error) {
+	now := time.Now()
+	msg.Status = OutboxStatusSent
+	msg.SentAt = &now
+	msg.Attempts++
+	return or.UpdateOutboxMessage(ctx, msg, WithColumns(Columns.OutboxMessage.Status, Columns.OutboxMessage.SentAt, Columns.OutboxMessage.Attempts))
+}
+
+// SetOutboxMessageFailed stores the delivery error and schedules the next attempt, or dead-letters the message if next is nil.
+func (or OutboxRepo) SetOutboxMessageFailed(ctx context.Context, msg *OutboxMessage, lastError string, next *time.Time) (bool, error) {
+	msg.Attempts++
+	msg.LastError = &lastError
+	if next == nil {
+		msg.Status = OutboxStatusDead
+	} else {
+		msg.NextAttemptAt = *next
+	}
+
+	return or.UpdateOutboxMessage(ctx, msg, WithColumns(Columns.OutboxMessage.Status, Columns.OutboxMessage.Attempts, Columns.OutboxMessage.LastError, Columns.OutboxMessage.NextAttemptAt))
+}
+
+// ResendOutboxMessage returns dead-lettered message to the queue for immediate delivery.
+func (or OutboxRepo) ResendOutboxMessage(ctx context.Context, id int) (bool, error) {
+	res, err := or.db.ModelContext(ctx, &OutboxMessage{}).
+		Set("? = ?", pg.Ident(Columns.OutboxMessage.Status), OutboxStatusPending).
+		Set("? = 0", pg.Ident(Columns.OutboxMessage.Attempts)).
+		Set("? = now()", pg.Ident(Columns.OutboxMessage.NextAttemptAt)).
+		Where("? = ?", pg.Ident(Columns.OutboxMessage.ID), id).
+		Where("? = ?", pg.Ident(Columns.OutboxMessage.Status), OutboxStatusDead).
+		Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
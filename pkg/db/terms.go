@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+type TermsRepo struct {
+	db      orm.DB
+	filters map[string][]Filter
+	sort    map[string][]SortField
+	join    map[string][]string
+}
+
+// NewTermsRepo returns new repository
+func NewTermsRepo(db orm.DB) TermsRepo {
+	return TermsRepo{
+		db: db,
+		filters: map[string][]Filter{
+			Tables.Term.Name:      {},
+			Tables.TermAlias.Name: {},
+		},
+		sort: map[string][]SortField{
+			Tables.Term.Name:      {{Column: Columns.Term.Name, Direction: SortAsc}},
+			Tables.TermAlias.Name: {{Column: Columns.TermAlias.Alias, Direction: SortAsc}},
+		},
+		join: map[string][]string{
+			Tables.Term.Name:      {TableColumns},
+			Tables.TermAlias.Name: {TableColumns, Columns.TermAlias.Term},
+		},
+	}
+}
+
+// WithTransaction is a function that wraps TermsRepo with pg.Tx transaction.
+func (tr TermsRepo) WithTransaction(tx *pg.Tx) TermsRepo {
+	tr.db = tx
+	return tr
+}
+
+/*** Term ***/
+
+// FullTerm returns full joins with all columns
+func (tr TermsRepo) FullTerm() OpFunc {
+	return WithColumns(tr.join[Tables.Term.Name]...)
+}
+
+// DefaultTermSort returns default sort.
+func (tr TermsRepo) DefaultTermSort() OpFunc {
+	return WithSort(tr.sort[Tables.Term.Name]...)
+}
+
+// TermByID is a function that returns Term by ID(s) or nil.
+func (tr TermsRepo) TermByID(ctx context.Context, id int, ops ...OpFunc) (*Term, error) {
+	return tr.OneTerm(ctx, &TermSearch{ID: &id}, ops...)
+}
+
+// OneTerm is a function that returns one Term by filters. It could return pg.ErrMultiRows.
+func (tr TermsRepo) OneTerm(ctx context.Context, search *TermSearch, ops ...OpFunc) (*Term, error) {
+	obj := &Term{}
+	err := buildQuery(ctx, tr.db, obj, search, tr.filters[Tables.Term.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// TermsByFilters returns Term list.
+func (tr TermsRepo) TermsByFilters(ctx context.Context, search *TermSearch, pager Pager, ops ...OpFunc) (terms []Term, err error) {
+	err = buildQuery(ctx, tr.db, &terms, search, tr.filters[Tables.Term.Name], pager, ops...).Select()
+	return
+}
+
+// CountTerms returns count
+func (tr TermsRepo) CountTerms(ctx context.Context, search *TermSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, tr.db, &Term{}, search, tr.filters[Tables.Term.Name], PagerOne, ops...).Count()
+}
+
+// AddTerm adds Term to DB.
+func (tr TermsRepo) AddTerm(ctx context.Context, term *Term, ops ...OpFunc) (*Term, error) {
+	q := tr.db.ModelContext(ctx, term)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Term.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return term, err
+}
+
+// UpdateTerm updates Term in DB.
+func (tr TermsRepo) UpdateTerm(ctx context.Context, term *Term, ops ...OpFunc) (bool, error) {
+	q := tr.db.ModelContext(ctx, term).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.Term.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteTerm deletes Term from DB.
+func (tr TermsRepo) DeleteTerm(ctx context.Context, id int) (deleted bool, err error) {
+	term := &Term{ID: id}
+
+	res, err := tr.db.ModelContext(ctx, term).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+/*** TermAlias ***/
+
+// FullTermAlias returns full joins with all columns
+func (tr TermsRepo) FullTermAlias() OpFunc {
+	return WithColumns(tr.join[Tables.TermAlias.Name]...)
+}
+
+// DefaultTermAliasSort returns default sort.
+func (tr TermsRepo) DefaultTermAliasSort() OpFunc {
+	return WithSort(tr.sort[Tables.TermAlias.Name]...)
+}
+
+// TermAliasByID is a function that returns TermAlias by ID(s) or nil.
+func (tr TermsRepo) TermAliasByID(ctx context.Context, id int, ops ...OpFunc) (*TermAlias, error) {
+	return tr.OneTermAlias(ctx, &TermAliasSearch{ID: &id}, ops...)
+}
+
+// OneTermAlias is a function that returns one TermAlias by filters. It could return pg.ErrMultiRows.
+func (tr TermsRepo) OneTermAlias(ctx context.Context, search *TermAliasSearch, ops ...OpFunc) (*TermAlias, error) {
+	obj := &TermAlias{}
+	err := buildQuery(ctx, tr.db, obj, search, tr.filters[Tables.TermAlias.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// TermAliasesByFilters returns TermAlias list.
+func (tr TermsRepo) TermAliasesByFilters(ctx context.Context, search *TermAliasSearch, pager Pager, ops ...OpFunc) (termAliases []TermAlias, err error) {
+	err = buildQuery(ctx, tr.db, &termAliases, search, tr.filters[Tables.TermAlias.Name], pager, ops...).Select()
+	return
+}
+
+// CountTermAliases returns count
+func (tr TermsRepo) CountTermAliases(ctx context.Context, search *TermAliasSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, tr.db, &TermAlias{}, search, tr.filters[Tables.TermAlias.Name], PagerOne, ops...).Count()
+}
+
+// AddTermAlias adds TermAlias to DB.
+func (tr TermsRepo) AddTermAlias(ctx context.Context, termAlias *TermAlias, ops ...OpFunc) (*TermAlias, error) {
+	q := tr.db.ModelContext(ctx, termAlias)
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.TermAlias.CreatedAt)
+	}
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return termAlias, err
+}
+
+// UpdateTermAlias updates TermAlias in DB.
+func (tr TermsRepo) UpdateTermAlias(ctx context.Context, termAlias *TermAlias, ops ...OpFunc) (bool, error) {
+	q := tr.db.ModelContext(ctx, termAlias).WherePK()
+	if len(ops) == 0 {
+		q = q.ExcludeColumn(Columns.TermAlias.CreatedAt)
+	}
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteTermAlias deletes TermAlias from DB.
+func (tr TermsRepo) DeleteTermAlias(ctx context.Context, id int) (deleted bool, err error) {
+	termAlias := &TermAlias{ID: id}
+
+	res, err := tr.db.ModelContext(ctx, termAlias).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/go-pg/pg/v10"
+)
+
+const (
+	// term kinds
+	TermKindCity       = "city"
+	TermKindUniversity = "university"
+
+	// term statuses
+	TermStatusApproved = "approved"
+	TermStatusPending  = "pending"
+)
+
+var (
+	ErrTermNotFound     = errors.New("term not found")
+	ErrTermKindMismatch = errors.New("terms are of different kinds")
+	ErrTermAliasTaken   = errors.New("alias belongs to another term")
+)
+
+// TermKey returns alias key of the value: lowercase letters and digits separated by single spaces, ё is replaced by е.
+func TermKey(value string) string {
+	value = strings.ReplaceAll(strings.ToLower(value), "ё", "е")
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// TermByAlias returns term of the kind having the alias key or nil.
+func (tr TermsRepo) TermByAlias(ctx context.Context, kind, key string) (*Term, error) {
+	search := &TermSearch{Kind: &kind}
+	search.With("? in (select ? from ? where ? = ? and ? = ?)",
+		pg.Ident(Tables.Term.Alias+"."+Columns.Term.ID), pg.Ident(Columns.TermAlias.TermID), pg.Ident(Tables.TermAlias.Name),
+		pg.Ident(Columns.TermAlias.Kind), kind, pg.Ident(Columns.TermAlias.Alias), key)
+
+	return tr.OneTerm(ctx, search)
+}
+
+// PendingTerms returns terms waiting for approval by administrators in order of appearance.
+func (tr TermsRepo) PendingTerms(ctx context.Context) ([]Term, error) {
+	return tr.TermsByFilters(ctx, &TermSearch{Statuses: []string{TermStatusPending}}, PagerNoLimit, WithSort(NewSortField(Columns.Term.ID, false)))
+}
+
+// TermAliasesOf returns aliases of the term.
+func (tr TermsRepo) TermAliasesOf(ctx context.Context, termID int) ([]TermAlias, error) {
+	return tr.TermAliasesByFilters(ctx, &TermAliasSearch{TermID: &termID}, PagerNoLimit, tr.DefaultTermAliasSort())
+}
+
+// NormalizeTerms replaces values by canonical names of their terms dropping empty and repeated ones.
+// Unknown values are added as pending terms and returned along with the names.
+func (tr TermsRepo) NormalizeTerms(ctx context.Context, kind string, values []string) ([]string, []Term, error) {
+	var (
+		names []string
+		added []Term
+		seen  = map[int]bool{}
+	)
+	for _, value := range values {
+		key := TermKey(value)
+		if key == "" {
+			continue
+		}
+
+		term, err := tr.TermByAlias(ctx, kind, key)
+		if err != nil {
+			return nil, nil, err
+		} else if term == nil {
+			if term, err = tr.addPendingTerm(ctx, kind, strings.TrimSpace(value), key); err != nil {
+				return nil, nil, err
+			} else if term.Status == TermStatusPending && !seen[term.ID] {
+				added = append(added, *term)
+			}
+		}
+
+		if !seen[term.ID] {
+			seen[term.ID] = true
+			names = append(names, term.Name)
+		}
+	}
+
+	return names, added, nil
+}
+
+// addPendingTerm adds the term with its name as alias. The term added concurrently with the same alias is returned instead.
+func (tr TermsRepo) addPendingTerm(ctx context.Context, kind, name, key string) (*Term, error) {
+	term, err := tr.AddTerm(ctx, &Term{Kind: kind, Name: name, Status: TermStatusPending})
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := tr.addTermAlias(ctx, term, key)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return term, nil
+	}
+
+	if _, err = tr.DeleteTerm(ctx, term.ID); err != nil {
+		return nil, err
+	}
+
+	term, err = tr.TermByAlias(ctx, kind, key)
+	if err == nil && term == nil {
+		err = ErrTermNotFound
+	}
+
+	return term, err
+}
+
+// addTermAlias adds alias key to the term, false is returned if the key is already taken.
+func (tr TermsRepo) addTermAlias(ctx context.Context, term *Term, key string) (bool, error) {
+	res, err := tr.db.ModelContext(ctx, &TermAlias{TermID: term.ID, Kind: term.Kind, Alias: key}).
+		ExcludeColumn(Columns.TermAlias.ID, Columns.TermAlias.CreatedAt).
+		OnConflict("DO NOTHING").
+		Insert()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// ApproveTerm marks the term as canonical, non-empty name renames it keeping the old name as alias. Members are re-normalised.
+func (tr TermsRepo) ApproveTerm(ctx context.Context, id int, name string) (*Term, error) {
+	term, err := tr.TermByID(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if term == nil {
+		return nil, ErrTermNotFound
+	}
+
+	if name = strings.TrimSpace(name); TermKey(name) != "" && name != term.Name {
+		if err = tr.ensureTermAlias(ctx, term, TermKey(name)); err != nil {
+			return nil, err
+		}
+		term.Name = name
+	}
+
+	term.Status = TermStatusApproved
+	if _, err = tr.UpdateTerm(ctx, term, WithColumns(Columns.Term.Name, Columns.Term.Status)); err != nil {
+		return nil, err
+	}
+
+	return term, tr.RenormalizeMembers(ctx)
+}
+
+// MergeTerm moves aliases of the term to the target one and deletes the term. Members are re-normalised.
+func (tr TermsRepo) MergeTerm(ctx context.Context, id, targetID int) (*Term, error) {
+	terms, err := tr.TermsByFilters(ctx, &TermSearch{IDs: []int{id, targetID}}, PagerNoLimit)
+	if err != nil {
+		return nil, err
+	} else if len(terms) != 2 {
+		return nil, ErrTermNotFound
+	} else if terms[0].Kind != terms[1].Kind {
+		return nil, ErrTermKindMismatch
+	}
+
+	_, err = tr.db.ModelContext(ctx, &TermAlias{}).
+		Set("? = ?", pg.Ident(Columns.TermAlias.TermID), targetID).
+		Where("? = ?", pg.Ident(Columns.TermAlias.TermID), id).
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tr.DeleteTerm(ctx, id); err != nil {
+		return nil, err
+	}
+
+	target := &terms[0]
+	if target.ID != targetID {
+		target = &terms[1]
+	}
+
+	return target, tr.RenormalizeMembers(ctx)
+}
+
+// AliasTerm adds alias of the term, values of members matching the alias are replaced by the term name.
+func (tr TermsRepo) AliasTerm(ctx context.Context, id int, alias string) error {
+	term, err := tr.TermByID(ctx, id)
+	if err != nil {
+		return err
+	} else if term == nil {
+		return ErrTermNotFound
+	}
+
+	if err = tr.ensureTermAlias(ctx, term, TermKey(alias)); err != nil {
+		return err
+	}
+
+	return tr.RenormalizeMembers(ctx)
+}
+
+// ensureTermAlias adds alias key to the term unless it has one, ErrTermAliasTaken is returned if the key belongs to another term.
+func (tr TermsRepo) ensureTermAlias(ctx context.Context, term *Term, key string) error {
+	cur, err := tr.TermByAlias(ctx, term.Kind, key)
+	if err != nil {
+		return err
+	} else if cur != nil && cur.ID != term.ID {
+		return ErrTermAliasTaken
+	} else if cur != nil {
+		return nil
+	}
+
+	if ok, err := tr.addTermAlias(ctx, term, key); err != nil {
+		return err
+	} else if !ok {
+		return ErrTermAliasTaken
+	}
+
+	return nil
+}
+
+// RenormalizeMembers replaces cities and universities of the directory by canonical names of their terms,
+// values unknown to the dictionary are kept.
+func (tr TermsRepo) RenormalizeMembers(ctx context.Context) error {
+	var aliases []TermAlias
+	if err := tr.db.ModelContext(ctx, &aliases).Relation(Columns.TermAlias.Term).Select(); err != nil {
+		return err
+	}
+
+	names := map[string]map[string]string{}
+	for _, a := range aliases {
+		if names[a.Kind] == nil {
+			names[a.Kind] = map[string]string{}
+		}
+		names[a.Kind][a.Alias] = a.Term.Name
+	}
+
+	dr := NewDirectoryRepo(tr.db)
+	members, err := dr.MembersByFilters(ctx, nil, PagerNoLimit)
+	if err != nil {
+		return err
+	}
+
+	for i := range members {
+		m := &members[i]
+		cities, universities := canonical(names[TermKindCity], m.Cities), canonical(names[TermKindUniversity], m.Universities)
+		if equalStrings(cities, m.Cities) && equalStrings(universities, m.Universities) {
+			continue
+		}
+
+		m.Cities, m.Universities = cities, universities
+		if _, err = dr.UpdateMember(ctx, m, WithColumns(Columns.Member.Cities, Columns.Member.Universities)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// canonical replaces values by names from the alias map dropping repeated ones.
+func canonical(names map[string]string, values []string) []string {
+	res := make([]string, 0, len(values))
+	seen := map[string]bool{}
+	for _, v := range values {
+		if name, ok := names[TermKey(v)]; ok {
+			v = name
+		}
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+type UpdatesRepo struct {
+	db      orm.DB
+	filters map[string][]Filter
+	sort    map[string][]SortField
+	join    map[string][]string
+}
+
+// NewUpdatesRepo returns new repository
+func NewUpdatesRepo(db orm.DB) UpdatesRepo {
+	return UpdatesRepo{
+		db: db,
+		filters: map[string][]Filter{
+			Tables.ProcessedUpdate.Name: {},
+		},
+		sort: map[string][]SortField{
+			Tables.ProcessedUpdate.Name: {{Column: Columns.ProcessedUpdate.ID, Direction: SortAsc}},
+		},
+		join: map[string][]string{
+			Tables.ProcessedUpdate.Name: {TableColumns},
+		},
+	}
+}
+
+// WithTransaction is a function that wraps UpdatesRepo with pg.Tx transaction.
+func (ur UpdatesRepo) WithTransaction(tx *pg.Tx) UpdatesRepo {
+	ur.db = tx
+	return ur
+}
+
+/*** ProcessedUpdate ***/
+
+// FullProcessedUpdate returns full joins with all columns
+func (ur UpdatesRepo) FullProcessedUpdate() OpFunc {
+	return WithColumns(ur.join[Tables.ProcessedUpdate.Name]...)
+}
+
+// DefaultProcessedUpdateSort returns default sort.
+func (ur UpdatesRepo) DefaultProcessedUpdateSort() OpFunc {
+	return WithSort(ur.sort[Tables.ProcessedUpdate.Name]...)
+}
+
+// ProcessedUpdateByID is a function that returns ProcessedUpdate by ID(s) or nil.
+func (ur UpdatesRepo) ProcessedUpdateByID(ctx context.Context, id int64, ops ...OpFunc) (*ProcessedUpdate, error) {
+	return ur.OneProcessedUpdate(ctx, &ProcessedUpdateSearch{ID: &id}, ops...)
+}
+
+// OneProcessedUpdate is a function that returns one ProcessedUpdate by filters. It could return pg.ErrMultiRows.
+func (ur UpdatesRepo) OneProcessedUpdate(ctx context.Context, search *ProcessedUpdateSearch, ops ...OpFunc) (*ProcessedUpdate, error) {
+	obj := &ProcessedUpdate{}
+	err := buildQuery(ctx, ur.db, obj, search, ur.filters[Tables.ProcessedUpdate.Name], PagerTwo, ops...).Select()
+
+	if errors.Is(err, pg.ErrMultiRows) {
+		return nil, err
+	} else if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// ProcessedUpdatesByFilters returns ProcessedUpdate list.
+func (ur UpdatesRepo) ProcessedUpdatesByFilters(ctx context.Context, search *ProcessedUpdateSearch, pager Pager, ops ...OpFunc) (processedUpdates []ProcessedUpdate, err error) {
+	err = buildQuery(ctx, ur.db, &processedUpdates, search, ur.filters[Tables.ProcessedUpdate.Name], pager, ops...).Select()
+	return
+}
+
+// CountProcessedUpdates returns count
+func (ur UpdatesRepo) CountProcessedUpdates(ctx context.Context, search *ProcessedUpdateSearch, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, ur.db, &ProcessedUpdate{}, search, ur.filters[Tables.ProcessedUpdate.Name], PagerOne, ops...).Count()
+}
+
+// AddProcessedUpdate adds ProcessedUpdate to DB.
+func (ur UpdatesRepo) AddProcessedUpdate(ctx context.Context, processedUpdate *ProcessedUpdate, ops ...OpFunc) (*ProcessedUpdate, error) {
+	q := ur.db.ModelContext(ctx, processedUpdate)
+	applyOps(q, ops...)
+	_, err := q.Insert()
+
+	return processedUpdate, err
+}
+
+// UpdateProcessedUpdate updates ProcessedUpdate in DB.
+func (ur UpdatesRepo) UpdateProcessedUpdate(ctx context.Context, processedUpdate *ProcessedUpdate, ops ...OpFunc) (bool, error) {
+	q := ur.db.ModelContext(ctx, processedUpdate).WherePK()
+	applyOps(q, ops...)
+	res, err := q.Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
+
+// DeleteProcessedUpdate deletes ProcessedUpdate from DB.
+func (ur UpdatesRepo) DeleteProcessedUpdate(ctx context.Context, id int64) (deleted bool, err error) {
+	processedUpdate := &ProcessedUpdate{ID: id}
+
+	res, err := ur.db.ModelContext(ctx, processedUpdate).WherePK().Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, err
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+const (
+	// processed update statuses
+	UpdateStatusProcessing = "processing"
+	UpdateStatusDone       = "done"
+)
+
+// ClaimUpdate stores the update as being processed by update.Owner. It returns false if the update was already processed
+// or is being processed by the owner which heartbeated after expiredBefore, claims of owners which stopped heartbeating are taken over.
+func (ur UpdatesRepo) ClaimUpdate(ctx context.Context, update *ProcessedUpdate, expiredBefore time.Time) (bool, error) {
+	update.Status = UpdateStatusProcessing
+	update.ClaimedAt = time.Now()
+	update.HeartbeatAt = update.ClaimedAt
+	res, err := ur.db.ModelContext(ctx, update).
+		OnConflict("(?) DO UPDATE", pg.Ident(Columns.ProcessedUpdate.ID)).
+		Set("? = EXCLUDED.?", pg.Ident(Columns.ProcessedUpdate.ClaimedAt), pg.Ident(Columns.ProcessedUpdate.ClaimedAt)).
+		Set("? = EXCLUDED.?", pg.Ident(Columns.ProcessedUpdate.Owner), pg.Ident(Columns.ProcessedUpdate.Owner)).
+		Set("? = EXCLUDED.?", pg.Ident(Columns.ProcessedUpdate.HeartbeatAt), pg.Ident(Columns.ProcessedUpdate.HeartbeatAt)).
+		Where("?.? = ?", pg.Ident(Tables.ProcessedUpdate.Alias), pg.Ident(Columns.ProcessedUpdate.Status), UpdateStatusProcessing).
+		Where("?.? < ?", pg.Ident(Tables.ProcessedUpdate.Alias), pg.Ident(Columns.ProcessedUpdate.HeartbeatAt), expiredBefore).
+		Insert()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// SetUpdateProcessed marks the update as processed.
+func (ur UpdatesRepo) SetUpdateProcessed(ctx context.Context, update *ProcessedUpdate) (bool, error) {
+	now := time.Now()
+	update.Status = UpdateStatusDone
+	update.ProcessedAt = &now
+	return ur.UpdateProcessedUpdate(ctx, update, WithColumns(Columns.ProcessedUpdate.Status, Columns.ProcessedUpdate.ProcessedAt))
+}
+
+// HeartbeatUpdates refreshes heartbeat of the updates being processed by the owner, so their claims are not taken over.
+func (ur UpdatesRepo) HeartbeatUpdates(ctx context.Context, owner string, ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res, err := ur.db.ModelContext(ctx, &ProcessedUpdate{}).
+		Set("? = ?", pg.Ident(Columns.ProcessedUpdate.HeartbeatAt), time.Now()).
+		Where("? IN (?)", pg.Ident(Columns.ProcessedUpdate.ID), pg.In(ids)).
+		Where("? = ?", pg.Ident(Columns.ProcessedUpdate.Owner), owner).
+		Where("? = ?", pg.Ident(Columns.ProcessedUpdate.Status), UpdateStatusProcessing).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// UnfinishedUpdates returns updates being processed whose owners stopped heartbeating before the given time.
+func (ur UpdatesRepo) UnfinishedUpdates(ctx context.Context, heartbeatBefore time.Time) ([]ProcessedUpdate, error) {
+	return ur.ProcessedUpdatesByFilters(ctx, &ProcessedUpdateSearch{
+		Statuses:      []string{UpdateStatusProcessing},
+		HeartbeatAtTo: &heartbeatBefore,
+	}, PagerNoLimit, ur.DefaultProcessedUpdateSort())
+}
+
+// LastUpdateID returns ID of the latest received update, updates up to it are confirmed to Telegram. Zero is returned if there are none.
+func (ur UpdatesRepo) LastUpdateID(ctx context.Context) (int64, error) {
+	var id int64
+	_, err := ur.db.QueryOneContext(ctx, pg.Scan(&id), "SELECT coalesce(max(?), 0) FROM ?",
+		pg.Ident(Columns.ProcessedUpdate.ID), pg.Ident(Tables.ProcessedUpdate.Name))
+	return id, err
+}
+
+// DeleteProcessedUpdates removes processed updates older than the given time, the latest update is kept as the offset.
+func (ur UpdatesRepo) DeleteProcessedUpdates(ctx context.Context, processedBefore time.Time) (int, error) {
+	res, err := ur.db.ModelContext(ctx, &ProcessedUpdate{}).
+		Where("? = ?", pg.Ident(Columns.ProcessedUpdate.Status), UpdateStatusDone).
+		Where("? < ?", pg.Ident(Columns.ProcessedUpdate.ProcessedAt), processedBefore).
+		Where("? < (SELECT max(?) FROM ?)", pg.Ident(Columns.ProcessedUpdate.ID), pg.Ident(Columns.ProcessedUpdate.ID), pg.Ident(Tables.ProcessedUpdate.Name)).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
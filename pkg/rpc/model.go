@@ -0,0 +1,207 @@
+package rpc
+
+import (
+	"encoding/json"
+	"time"
+
+	"botsrv/pkg/db"
+)
+
+const maxPageSize = 500
+
+type ViewOps struct {
+	Page     int `json:"page"`     // page number, default - 1
+	PageSize int `json:"pageSize"` // items count per page, max - 500
+}
+
+// Pager returns db.Pager for view options.
+func (v *ViewOps) Pager() db.Pager {
+	if v == nil {
+		return db.PagerDefault
+	}
+
+	pageSize := v.PageSize
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return db.NewPager(v.Page, pageSize)
+}
+
+type ModerationLog struct {
+	ID            int       `json:"id"`
+	ApplicationID *int      `json:"applicationId"`
+	TargetTgID    int64     `json:"targetTgId"`
+	ActorTgID     int64     `json:"actorTgId"`
+	ActorName     string    `json:"actorName"`
+	Action        string    `json:"action"`
+	StatusBefore  *string   `json:"statusBefore"`
+	StatusAfter   *string   `json:"statusAfter"`
+	Comment       *string   `json:"comment"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func newModerationLog(in *db.ModerationLog) *ModerationLog {
+	if in == nil {
+		return nil
+	}
+
+	return &ModerationLog{
+		ID:            in.ID,
+		ApplicationID: in.ApplicationID,
+		TargetTgID:    in.TargetTgID,
+		ActorTgID:     in.ActorTgID,
+		ActorName:     in.ActorName,
+		Action:        in.Action,
+		StatusBefore:  in.StatusBefore,
+		StatusAfter:   in.StatusAfter,
+		Comment:       in.Comment,
+		CreatedAt:     in.CreatedAt,
+	}
+}
+
+type ModerationLogSearch struct {
+	ApplicationID  *int       `json:"applicationId"`
+	TargetTgID     *int64     `json:"targetTgId"`
+	ActorTgID      *int64     `json:"actorTgId"`
+	ActorNameILike *string    `json:"actorNameILike"`
+	Actions        []string   `json:"actions"`
+	CreatedAtFrom  *time.Time `json:"createdAtFrom"`
+	CreatedAtTo    *time.Time `json:"createdAtTo"`
+}
+
+func (s *ModerationLogSearch) ToDB() *db.ModerationLogSearch {
+	if s == nil {
+		return nil
+	}
+
+	return &db.ModerationLogSearch{
+		ApplicationID:  s.ApplicationID,
+		TargetTgID:     s.TargetTgID,
+		ActorTgID:      s.ActorTgID,
+		ActorNameILike: s.ActorNameILike,
+		Actions:        s.Actions,
+		CreatedAtFrom:  s.CreatedAtFrom,
+		CreatedAtTo:    s.CreatedAtTo,
+	}
+}
+
+type OutboxMessage struct {
+	ID            int             `json:"id"`
+	Method        string          `json:"method"`
+	ChatID        int64           `json:"chatId"`
+	Payload       json.RawMessage `json:"payload"`
+	ApplicationID *int            `json:"applicationId"`
+	Kind          *string         `json:"kind"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	LastError     *string         `json:"lastError"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	SentAt        *time.Time      `json:"sentAt"`
+}
+
+func newOutboxMessage(in *db.OutboxMessage) *OutboxMessage {
+	if in == nil {
+		return nil
+	}
+
+	return &OutboxMessage{
+		ID:            in.ID,
+		Method:        in.Method,
+		ChatID:        in.ChatID,
+		Payload:       in.Payload,
+		ApplicationID: in.ApplicationID,
+		Kind:          in.Kind,
+		Status:        in.Status,
+		Attempts:      in.Attempts,
+		NextAttemptAt: in.NextAttemptAt,
+		LastError:     in.LastError,
+		CreatedAt:     in.CreatedAt,
+		SentAt:        in.SentAt,
+	}
+}
+
+type OutboxMessageSearch struct {
+	ChatID        *int64     `json:"chatId"`
+	ApplicationID *int       `json:"applicationId"`
+	Method        *string    `json:"method"`
+	Statuses      []string   `json:"statuses"`
+	CreatedAtFrom *time.Time `json:"createdAtFrom"`
+	CreatedAtTo   *time.Time `json:"createdAtTo"`
+}
+
+func (s *OutboxMessageSearch) ToDB() *db.OutboxMessageSearch {
+	if s == nil {
+		return nil
+	}
+
+	return &db.OutboxMessageSearch{
+		ChatID:        s.ChatID,
+		ApplicationID: s.ApplicationID,
+		Method:        s.Method,
+		Statuses:      s.Statuses,
+		CreatedAtFrom: s.CreatedAtFrom,
+		CreatedAtTo:   s.CreatedAtTo,
+	}
+}
+
+type Term struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newTerm(in *db.Term) *Term {
+	if in == nil {
+		return nil
+	}
+
+	return &Term{
+		ID:        in.ID,
+		Kind:      in.Kind,
+		Name:      in.Name,
+		Status:    in.Status,
+		CreatedAt: in.CreatedAt,
+	}
+}
+
+type TermSearch struct {
+	Kind      *string  `json:"kind"`
+	Statuses  []string `json:"statuses"`
+	NameILike *string  `json:"nameILike"`
+}
+
+func (s *TermSearch) ToDB() *db.TermSearch {
+	if s == nil {
+		return nil
+	}
+
+	return &db.TermSearch{
+		Kind:      s.Kind,
+		Statuses:  s.Statuses,
+		NameILike: s.NameILike,
+	}
+}
+
+type TermAlias struct {
+	ID        int       `json:"id"`
+	TermID    int       `json:"termId"`
+	Alias     string    `json:"alias"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newTermAlias(in *db.TermAlias) *TermAlias {
+	if in == nil {
+		return nil
+	}
+
+	return &TermAlias{
+		ID:        in.ID,
+		TermID:    in.TermID,
+		Alias:     in.Alias,
+		CreatedAt: in.CreatedAt,
+	}
+}
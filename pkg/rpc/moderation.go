@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+
+	"botsrv/pkg/db"
+	"botsrv/pkg/embedlog"
+
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+type ModerationService struct {
+	zenrpc.Service
+	embedlog.Logger
+	admissionRepo db.AdmissionRepo
+}
+
+func NewModerationService(dbo db.DB, logger embedlog.Logger) *ModerationService {
+	return &ModerationService{
+		Logger:        logger,
+		admissionRepo: db.NewAdmissionRepo(dbo),
+	}
+}
+
+// CountLogs returns count of moderation log entries according to conditions in search params.
+//
+//zenrpc:search ModerationLogSearch
+//zenrpc:return int
+//zenrpc:500 Internal Error
+func (s ModerationService) CountLogs(ctx context.Context, search *ModerationLogSearch) (int, error) {
+	count, err := s.admissionRepo.CountModerationLogs(ctx, search.ToDB())
+	if err != nil {
+		return 0, internalError(err)
+	}
+
+	return count, nil
+}
+
+// GetLogs returns a list of moderation log entries according to conditions in search params, newest first.
+//
+//zenrpc:search ModerationLogSearch
+//zenrpc:viewOps ViewOps
+//zenrpc:return []ModerationLog
+//zenrpc:500 Internal Error
+func (s ModerationService) GetLogs(ctx context.Context, search *ModerationLogSearch, viewOps *ViewOps) ([]ModerationLog, error) {
+	list, err := s.admissionRepo.ModerationLogsByFilters(ctx, search.ToDB(), viewOps.Pager(), s.admissionRepo.DefaultModerationLogSort())
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	res := make([]ModerationLog, 0, len(list))
+	for i := range list {
+		res = append(res, *newModerationLog(&list[i]))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	"botsrv/pkg/db"
+	"botsrv/pkg/embedlog"
+
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+var ErrNotDead = zenrpc.NewStringError(http.StatusBadRequest, "Message is not dead-lettered")
+
+type OutboxService struct {
+	zenrpc.Service
+	embedlog.Logger
+	outboxRepo db.OutboxRepo
+}
+
+func NewOutboxService(dbo db.DB, logger embedlog.Logger) *OutboxService {
+	return &OutboxService{
+		Logger:     logger,
+		outboxRepo: db.NewOutboxRepo(dbo),
+	}
+}
+
+// CountMessages returns count of outgoing messages according to conditions in search params.
+//
+//zenrpc:search OutboxMessageSearch
+//zenrpc:return int
+//zenrpc:500 Internal Error
+func (s OutboxService) CountMessages(ctx context.Context, search *OutboxMessageSearch) (int, error) {
+	count, err := s.outboxRepo.CountOutboxMessages(ctx, search.ToDB())
+	if err != nil {
+		return 0, internalError(err)
+	}
+
+	return count, nil
+}
+
+// GetMessages returns a list of outgoing messages according to conditions in search params in order of queueing.
+// Use statuses ["dead"] to get messages which were not delivered.
+//
+//zenrpc:search OutboxMessageSearch
+//zenrpc:viewOps ViewOps
+//zenrpc:return []OutboxMessage
+//zenrpc:500 Internal Error
+func (s OutboxService) GetMessages(ctx context.Context, search *OutboxMessageSearch, viewOps *ViewOps) ([]OutboxMessage, error) {
+	list, err := s.outboxRepo.OutboxMessagesByFilters(ctx, search.ToDB(), viewOps.Pager(), s.outboxRepo.DefaultOutboxMessageSort())
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	res := make([]OutboxMessage, 0, len(list))
+	for i := range list {
+		res = append(res, *newOutboxMessage(&list[i]))
+	}
+
+	return res, nil
+}
+
+// Resend returns dead-lettered message to the queue, it is delivered by the worker right away.
+//
+//zenrpc:id message id
+//zenrpc:return bool
+//zenrpc:400 Message is not dead-lettered
+//zenrpc:500 Internal Error
+func (s OutboxService) Resend(ctx context.Context, id int) (bool, error) {
+	ok, err := s.outboxRepo.ResendOutboxMessage(ctx, id)
+	if err != nil {
+		return false, internalError(err)
+	} else if !ok {
+		return false, ErrNotDead
+	}
+
+	return true, nil
+}
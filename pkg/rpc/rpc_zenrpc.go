@@ -0,0 +1,1072 @@
+// Code generated by zenrpc v2.2.9; DO NOT EDIT.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/vmkteam/zenrpc/v2"
+	"github.com/vmkteam/zenrpc/v2/smd"
+)
+
+var RPC = struct {
+	ModerationService struct{ CountLogs, GetLogs string }
+	OutboxService     struct{ CountMessages, GetMessages, Resend string }
+	TermsService      struct{ CountTerms, GetTerms, GetAliases, Approve, Merge, AddAlias string }
+}{
+	ModerationService: struct{ CountLogs, GetLogs string }{
+		CountLogs: "countlogs",
+		GetLogs:   "getlogs",
+	},
+	OutboxService: struct{ CountMessages, GetMessages, Resend string }{
+		CountMessages: "countmessages",
+		GetMessages:   "getmessages",
+		Resend:        "resend",
+	},
+	TermsService: struct{ CountTerms, GetTerms, GetAliases, Approve, Merge, AddAlias string }{
+		CountTerms: "countterms",
+		GetTerms:   "getterms",
+		GetAliases: "getaliases",
+		Approve:    "approve",
+		Merge:      "merge",
+		AddAlias:   "addalias",
+	},
+}
+
+func (ModerationService) SMD() smd.ServiceInfo {
+	return smd.ServiceInfo{
+		Methods: map[string]smd.Service{
+			"CountLogs": {
+				Description: `CountLogs returns count of moderation log entries according to conditions in search params.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "search",
+						Optional:    true,
+						Description: `ModerationLogSearch`,
+						Type:        smd.Object,
+						TypeName:    "ModerationLogSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:     "applicationId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "targetTgId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "actorTgId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "actorNameILike",
+								Optional: true,
+								Type:     smd.String,
+							},
+							{
+								Name: "actions",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name:     "createdAtFrom",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+							{
+								Name:     "createdAtTo",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"time.Time": {
+								Type:       "object",
+								Properties: smd.PropertyList{},
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `int`,
+					Type:        smd.Integer,
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+			"GetLogs": {
+				Description: `GetLogs returns a list of moderation log entries according to conditions in search params, newest first.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "search",
+						Optional:    true,
+						Description: `ModerationLogSearch`,
+						Type:        smd.Object,
+						TypeName:    "ModerationLogSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:     "applicationId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "targetTgId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "actorTgId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "actorNameILike",
+								Optional: true,
+								Type:     smd.String,
+							},
+							{
+								Name: "actions",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name:     "createdAtFrom",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+							{
+								Name:     "createdAtTo",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"time.Time": {
+								Type:       "object",
+								Properties: smd.PropertyList{},
+							},
+						},
+					},
+					{
+						Name:        "viewOps",
+						Optional:    true,
+						Description: `ViewOps`,
+						Type:        smd.Object,
+						TypeName:    "ViewOps",
+						Properties: smd.PropertyList{
+							{
+								Name:        "page",
+								Description: `page number, default - 1`,
+								Type:        smd.Integer,
+							},
+							{
+								Name:        "pageSize",
+								Description: `items count per page, max - 500`,
+								Type:        smd.Integer,
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `[]ModerationLog`,
+					Type:        smd.Array,
+					TypeName:    "[]ModerationLog",
+					Items: map[string]string{
+						"$ref": "#/definitions/ModerationLog",
+					},
+					Definitions: map[string]smd.Definition{
+						"ModerationLog": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name:     "applicationId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "targetTgId",
+									Type: smd.Integer,
+								},
+								{
+									Name: "actorTgId",
+									Type: smd.Integer,
+								},
+								{
+									Name: "actorName",
+									Type: smd.String,
+								},
+								{
+									Name: "action",
+									Type: smd.String,
+								},
+								{
+									Name:     "statusBefore",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:     "statusAfter",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:     "comment",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name: "createdAt",
+									Ref:  "#/definitions/time.Time",
+									Type: smd.Object,
+								},
+							},
+						},
+						"time.Time": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s ModerationService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
+
+	switch method {
+	case RPC.ModerationService.CountLogs:
+		var args = struct {
+			Search *ModerationLogSearch `json:"search"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CountLogs(ctx, args.Search))
+
+	case RPC.ModerationService.GetLogs:
+		var args = struct {
+			Search  *ModerationLogSearch `json:"search"`
+			ViewOps *ViewOps             `json:"viewOps"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search", "viewOps"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetLogs(ctx, args.Search, args.ViewOps))
+
+	default:
+		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
+	}
+
+	return resp
+}
+
+func (OutboxService) SMD() smd.ServiceInfo {
+	return smd.ServiceInfo{
+		Methods: map[string]smd.Service{
+			"CountMessages": {
+				Description: `CountMessages returns count of outgoing messages according to conditions in search params.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "search",
+						Optional:    true,
+						Description: `OutboxMessageSearch`,
+						Type:        smd.Object,
+						TypeName:    "OutboxMessageSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:     "chatId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "applicationId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "method",
+								Optional: true,
+								Type:     smd.String,
+							},
+							{
+								Name: "statuses",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name:     "createdAtFrom",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+							{
+								Name:     "createdAtTo",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"time.Time": {
+								Type:       "object",
+								Properties: smd.PropertyList{},
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `int`,
+					Type:        smd.Integer,
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+			"GetMessages": {
+				Description: `GetMessages returns a list of outgoing messages according to conditions in search params in order of queueing.
+Use statuses ["dead"] to get messages which were not delivered.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "search",
+						Optional:    true,
+						Description: `OutboxMessageSearch`,
+						Type:        smd.Object,
+						TypeName:    "OutboxMessageSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:     "chatId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "applicationId",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "method",
+								Optional: true,
+								Type:     smd.String,
+							},
+							{
+								Name: "statuses",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name:     "createdAtFrom",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+							{
+								Name:     "createdAtTo",
+								Optional: true,
+								Ref:      "#/definitions/time.Time",
+								Type:     smd.Object,
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"time.Time": {
+								Type:       "object",
+								Properties: smd.PropertyList{},
+							},
+						},
+					},
+					{
+						Name:        "viewOps",
+						Optional:    true,
+						Description: `ViewOps`,
+						Type:        smd.Object,
+						TypeName:    "ViewOps",
+						Properties: smd.PropertyList{
+							{
+								Name:        "page",
+								Description: `page number, default - 1`,
+								Type:        smd.Integer,
+							},
+							{
+								Name:        "pageSize",
+								Description: `items count per page, max - 500`,
+								Type:        smd.Integer,
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `[]OutboxMessage`,
+					Type:        smd.Array,
+					TypeName:    "[]OutboxMessage",
+					Items: map[string]string{
+						"$ref": "#/definitions/OutboxMessage",
+					},
+					Definitions: map[string]smd.Definition{
+						"OutboxMessage": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "method",
+									Type: smd.String,
+								},
+								{
+									Name: "chatId",
+									Type: smd.Integer,
+								},
+								{
+									Name: "payload",
+									Ref:  "#/definitions/json.RawMessage",
+									Type: smd.Object,
+								},
+								{
+									Name:     "applicationId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name:     "kind",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name: "status",
+									Type: smd.String,
+								},
+								{
+									Name: "attempts",
+									Type: smd.Integer,
+								},
+								{
+									Name: "nextAttemptAt",
+									Ref:  "#/definitions/time.Time",
+									Type: smd.Object,
+								},
+								{
+									Name:     "lastError",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name: "createdAt",
+									Ref:  "#/definitions/time.Time",
+									Type: smd.Object,
+								},
+								{
+									Name:     "sentAt",
+									Optional: true,
+									Ref:      "#/definitions/time.Time",
+									Type:     smd.Object,
+								},
+							},
+						},
+						"json.RawMessage": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+						"time.Time": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+			"Resend": {
+				Description: `Resend returns dead-lettered message to the queue, it is delivered by the worker right away.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `message id`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `bool`,
+					Type:        smd.Boolean,
+				},
+				Errors: map[int]string{
+					400: "Message is not dead-lettered",
+					500: "Internal Error",
+				},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s OutboxService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
+
+	switch method {
+	case RPC.OutboxService.CountMessages:
+		var args = struct {
+			Search *OutboxMessageSearch `json:"search"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CountMessages(ctx, args.Search))
+
+	case RPC.OutboxService.GetMessages:
+		var args = struct {
+			Search  *OutboxMessageSearch `json:"search"`
+			ViewOps *ViewOps             `json:"viewOps"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search", "viewOps"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetMessages(ctx, args.Search, args.ViewOps))
+
+	case RPC.OutboxService.Resend:
+		var args = struct {
+			Id int `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Resend(ctx, args.Id))
+
+	default:
+		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
+	}
+
+	return resp
+}
+
+func (TermsService) SMD() smd.ServiceInfo {
+	return smd.ServiceInfo{
+		Methods: map[string]smd.Service{
+			"CountTerms": {
+				Description: `CountTerms returns count of cities and universities of the dictionary according to conditions in search params.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "search",
+						Optional:    true,
+						Description: `TermSearch`,
+						Type:        smd.Object,
+						TypeName:    "TermSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:     "kind",
+								Optional: true,
+								Type:     smd.String,
+							},
+							{
+								Name: "statuses",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name:     "nameILike",
+								Optional: true,
+								Type:     smd.String,
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `int`,
+					Type:        smd.Integer,
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+			"GetTerms": {
+				Description: `GetTerms returns a list of cities and universities of the dictionary according to conditions in search params sorted by name.
+Use statuses ["pending"] to get values waiting for approval.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "search",
+						Optional:    true,
+						Description: `TermSearch`,
+						Type:        smd.Object,
+						TypeName:    "TermSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:     "kind",
+								Optional: true,
+								Type:     smd.String,
+							},
+							{
+								Name: "statuses",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name:     "nameILike",
+								Optional: true,
+								Type:     smd.String,
+							},
+						},
+					},
+					{
+						Name:        "viewOps",
+						Optional:    true,
+						Description: `ViewOps`,
+						Type:        smd.Object,
+						TypeName:    "ViewOps",
+						Properties: smd.PropertyList{
+							{
+								Name:        "page",
+								Description: `page number, default - 1`,
+								Type:        smd.Integer,
+							},
+							{
+								Name:        "pageSize",
+								Description: `items count per page, max - 500`,
+								Type:        smd.Integer,
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `[]Term`,
+					Type:        smd.Array,
+					TypeName:    "[]Term",
+					Items: map[string]string{
+						"$ref": "#/definitions/Term",
+					},
+					Definitions: map[string]smd.Definition{
+						"Term": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "kind",
+									Type: smd.String,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "status",
+									Type: smd.String,
+								},
+								{
+									Name: "createdAt",
+									Ref:  "#/definitions/time.Time",
+									Type: smd.Object,
+								},
+							},
+						},
+						"time.Time": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+			"GetAliases": {
+				Description: `GetAliases returns aliases of the term, they are lowercase letters and digits separated by spaces.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `term id`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `[]TermAlias`,
+					Type:        smd.Array,
+					TypeName:    "[]TermAlias",
+					Items: map[string]string{
+						"$ref": "#/definitions/TermAlias",
+					},
+					Definitions: map[string]smd.Definition{
+						"TermAlias": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "termId",
+									Type: smd.Integer,
+								},
+								{
+									Name: "alias",
+									Type: smd.String,
+								},
+								{
+									Name: "createdAt",
+									Ref:  "#/definitions/time.Time",
+									Type: smd.Object,
+								},
+							},
+						},
+						"time.Time": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					500: "Internal Error",
+				},
+			},
+			"Approve": {
+				Description: `Approve marks the term as canonical, non-empty name renames it keeping the old name as alias.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `term id`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "name",
+						Optional:    true,
+						Description: `new name of the term`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `Term`,
+					Optional:    true,
+					Type:        smd.Object,
+					TypeName:    "Term",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.Integer,
+						},
+						{
+							Name: "kind",
+							Type: smd.String,
+						},
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name: "status",
+							Type: smd.String,
+						},
+						{
+							Name: "createdAt",
+							Ref:  "#/definitions/time.Time",
+							Type: smd.Object,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"time.Time": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Alias belongs to another term",
+					404: "Term not found",
+					500: "Internal Error",
+				},
+			},
+			"Merge": {
+				Description: `Merge makes the term an alias of the target term, members having the term are moved to the target.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `term id`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "targetId",
+						Description: `target term id`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `Term`,
+					Optional:    true,
+					Type:        smd.Object,
+					TypeName:    "Term",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.Integer,
+						},
+						{
+							Name: "kind",
+							Type: smd.String,
+						},
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name: "status",
+							Type: smd.String,
+						},
+						{
+							Name: "createdAt",
+							Ref:  "#/definitions/time.Time",
+							Type: smd.Object,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"time.Time": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Terms are of different kinds",
+					404: "Term not found",
+					500: "Internal Error",
+				},
+			},
+			"AddAlias": {
+				Description: `AddAlias adds alias of the term, members having the alias get the term name.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `term id`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "alias",
+						Description: `alias of the term`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `bool`,
+					Type:        smd.Boolean,
+				},
+				Errors: map[int]string{
+					400: "Alias belongs to another term",
+					404: "Term not found",
+					500: "Internal Error",
+				},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s TermsService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
+
+	switch method {
+	case RPC.TermsService.CountTerms:
+		var args = struct {
+			Search *TermSearch `json:"search"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CountTerms(ctx, args.Search))
+
+	case RPC.TermsService.GetTerms:
+		var args = struct {
+			Search  *TermSearch `json:"search"`
+			ViewOps *ViewOps    `json:"viewOps"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search", "viewOps"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetTerms(ctx, args.Search, args.ViewOps))
+
+	case RPC.TermsService.GetAliases:
+		var args = struct {
+			Id int `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetAliases(ctx, args.Id))
+
+	case RPC.TermsService.Approve:
+		var args = struct {
+			Id   int     `json:"id"`
+			Name *string `json:"name"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id", "name"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Approve(ctx, args.Id, args.Name))
+
+	case RPC.TermsService.Merge:
+		var args = struct {
+			Id       int `json:"id"`
+			TargetId int `json:"targetId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id", "targetId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Merge(ctx, args.Id, args.TargetId))
+
+	case RPC.TermsService.AddAlias:
+		var args = struct {
+			Id    int    `json:"id"`
+			Alias string `json:"alias"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id", "alias"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.AddAlias(ctx, args.Id, args.Alias))
+
+	default:
+		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
+	}
+
+	return resp
+}
@@ -49,13 +49,14 @@ func New(dbo db.DB, logger embedlog.Logger, isDevel bool) zenrpc.Server {
 
 	// services
 	rpc.RegisterAll(map[string]zenrpc.Invoker{
-		//"sample": NewSampleService(db, logger),
+		"moderation": NewModerationService(dbo, logger),
+		"outbox":     NewOutboxService(dbo, logger),
+		"terms":      NewTermsService(dbo, logger),
 	})
 
 	return rpc
 }
 
-//nolint:unused
 func internalError(err error) *zenrpc.Error {
 	return zenrpc.NewError(http.StatusInternalServerError, err)
 }
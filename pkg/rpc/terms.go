@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"botsrv/pkg/db"
+	"botsrv/pkg/embedlog"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+var (
+	ErrTermNotFound     = zenrpc.NewStringError(http.StatusNotFound, "Term not found")
+	ErrTermKindMismatch = zenrpc.NewStringError(http.StatusBadRequest, "Terms are of different kinds")
+	ErrTermAliasTaken   = zenrpc.NewStringError(http.StatusBadRequest, "Alias belongs to another term")
+)
+
+type TermsService struct {
+	zenrpc.Service
+	embedlog.Logger
+	dbo       db.DB
+	termsRepo db.TermsRepo
+}
+
+func NewTermsService(dbo db.DB, logger embedlog.Logger) *TermsService {
+	return &TermsService{
+		Logger:    logger,
+		dbo:       dbo,
+		termsRepo: db.NewTermsRepo(dbo),
+	}
+}
+
+// CountTerms returns count of cities and universities of the dictionary according to conditions in search params.
+//
+//zenrpc:search TermSearch
+//zenrpc:return int
+//zenrpc:500 Internal Error
+func (s TermsService) CountTerms(ctx context.Context, search *TermSearch) (int, error) {
+	count, err := s.termsRepo.CountTerms(ctx, search.ToDB())
+	if err != nil {
+		return 0, internalError(err)
+	}
+
+	return count, nil
+}
+
+// GetTerms returns a list of cities and universities of the dictionary according to conditions in search params sorted by name.
+// Use statuses ["pending"] to get values waiting for approval.
+//
+//zenrpc:search TermSearch
+//zenrpc:viewOps ViewOps
+//zenrpc:return []Term
+//zenrpc:500 Internal Error
+func (s TermsService) GetTerms(ctx context.Context, search *TermSearch, viewOps *ViewOps) ([]Term, error) {
+	list, err := s.termsRepo.TermsByFilters(ctx, search.ToDB(), viewOps.Pager(), s.termsRepo.DefaultTermSort())
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	res := make([]Term, 0, len(list))
+	for i := range list {
+		res = append(res, *newTerm(&list[i]))
+	}
+
+	return res, nil
+}
+
+// GetAliases returns aliases of the term, they are lowercase letters and digits separated by spaces.
+//
+//zenrpc:id term id
+//zenrpc:return []TermAlias
+//zenrpc:500 Internal Error
+func (s TermsService) GetAliases(ctx context.Context, id int) ([]TermAlias, error) {
+	list, err := s.termsRepo.TermAliasesOf(ctx, id)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	res := make([]TermAlias, 0, len(list))
+	for i := range list {
+		res = append(res, *newTermAlias(&list[i]))
+	}
+
+	return res, nil
+}
+
+// Approve marks the term as canonical, non-empty name renames it keeping the old name as alias.
+//
+//zenrpc:id term id
+//zenrpc:name new name of the term
+//zenrpc:return Term
+//zenrpc:400 Alias belongs to another term
+//zenrpc:404 Term not found
+//zenrpc:500 Internal Error
+func (s TermsService) Approve(ctx context.Context, id int, name *string) (*Term, error) {
+	newName := ""
+	if name != nil {
+		newName = *name
+	}
+
+	var term *db.Term
+	err := s.dbo.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		term, err = s.termsRepo.WithTransaction(tx).ApproveTerm(ctx, id, newName)
+		return err
+	})
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	return newTerm(term), nil
+}
+
+// Merge makes the term an alias of the target term, members having the term are moved to the target.
+//
+//zenrpc:id term id
+//zenrpc:targetId target term id
+//zenrpc:return Term
+//zenrpc:400 Terms are of different kinds
+//zenrpc:404 Term not found
+//zenrpc:500 Internal Error
+func (s TermsService) Merge(ctx context.Context, id, targetId int) (*Term, error) {
+	var term *db.Term
+	err := s.dbo.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		term, err = s.termsRepo.WithTransaction(tx).MergeTerm(ctx, id, targetId)
+		return err
+	})
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	return newTerm(term), nil
+}
+
+// AddAlias adds alias of the term, members having the alias get the term name.
+//
+//zenrpc:id term id
+//zenrpc:alias alias of the term
+//zenrpc:return bool
+//zenrpc:400 Alias belongs to another term
+//zenrpc:404 Term not found
+//zenrpc:500 Internal Error
+func (s TermsService) AddAlias(ctx context.Context, id int, alias string) (bool, error) {
+	err := s.dbo.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		return s.termsRepo.WithTransaction(tx).AliasTerm(ctx, id, alias)
+	})
+	if err != nil {
+		return false, termError(err)
+	}
+
+	return true, nil
+}
+
+// termError converts dictionary errors to RPC errors.
+func termError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrTermNotFound):
+		return ErrTermNotFound
+	case errors.Is(err, db.ErrTermKindMismatch):
+		return ErrTermKindMismatch
+	case errors.Is(err, db.ErrTermAliasTaken):
+		return ErrTermAliasTaken
+	}
+
+	return internalError(err)
+}